@@ -0,0 +1,63 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Origin keywords for AllowDirective.
+const (
+	AllowOriginAll  = "*"
+	AllowOriginNone = "'none'"
+	AllowOriginSelf = "'self'"
+	AllowOriginSrc  = "'src'"
+)
+
+var (
+	// Matches permissions policy feature names.
+	reAllowFeature = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+)
+
+// A single feature of a permissions policy.  If Origins is empty, the
+// feature is allowed for the iframe's src origin.
+type AllowDirective struct {
+	Feature string
+	Origins []string
+}
+
+// A permissions policy for the allow attribute of <iframe>:
+//
+//	policy := AllowPolicy{}.Add("camera", AllowOriginSelf).Add("fullscreen")
+type AllowPolicy []AllowDirective
+
+// Returns a copy of p with the given feature appended.
+func (p AllowPolicy) Add(feature string, origins ...string) AllowPolicy {
+	result := make(AllowPolicy, len(p), len(p)+1)
+	copy(result, p)
+	return append(result, AllowDirective{Feature: feature, Origins: origins})
+}
+
+// Serializes p as an allow attribute value.  Directives with invalid feature
+// names and origins that could terminate a directive are omitted.
+func (p AllowPolicy) String() string {
+	directives := make([]string, 0, len(p))
+	for _, d := range p {
+		if !reAllowFeature.MatchString(d.Feature) {
+			continue
+		}
+
+		tokens := []string{d.Feature}
+		for _, origin := range d.Origins {
+			if len(origin) == 0 || strings.ContainsAny(origin, " \t\n\f\r;,\"") {
+				continue
+			}
+			tokens = append(tokens, origin)
+		}
+		directives = append(directives, strings.Join(tokens, " "))
+	}
+	return strings.Join(directives, "; ")
+}
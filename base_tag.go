@@ -6,10 +6,15 @@ package htmlgen
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // The base fields for all tags.
@@ -17,12 +22,26 @@ type baseTag struct {
 	htmlGen
 
 	tagType int
+	// The element name for kTagTypeCustom tags.
+	name string
 
 	// Tag attributes.
 	attrs map[int]string
 
 	customAttrs map[string]string
 
+	// Ordered set of classes.  attrs[kAttrClass] mirrors this for
+	// rendering.
+	classes []string
+
+	// Ordered inline style properties.  attrs[kAttrStyle] mirrors this
+	// for rendering.
+	styles []styleProperty
+
+	// The document of an <iframe>'s srcdoc attribute, which is rendered
+	// each time the tag is written rather than cached.
+	srcdoc Tag
+
 	children []tagWriter
 	parent   Tag
 
@@ -30,6 +49,8 @@ type baseTag struct {
 	isCacheClean bool
 	// Caches the rendered open tag, including attributes.
 	cacheOpen string
+	// The mode with which cacheOpen was rendered.
+	cacheMode RenderMode
 
 	// Hides the tag during the rendering process if true.
 	hidden bool
@@ -60,30 +81,55 @@ func (t *baseTag) AddClass(classes ...string) Tag {
 }
 
 func (t *baseTag) AddClasses(classes []string) Tag {
-	if classes == nil || len(classes) == 0 {
-		return t
-	} else {
-		classesStr := strings.Join(classes, " ")
-
-		oldClassesStr := t.attrs[kAttrClass]
-		if len(oldClassesStr) > 0 {
-			t.attrs[kAttrClass] += " " + classesStr
-		} else {
-			t.attrs[kAttrClass] = classesStr
+	for _, class := range splitClasses(classes) {
+		if !t.HasClass(class) {
+			t.classes = append(t.classes, class)
 		}
 	}
 
-	t.isCacheClean = false
+	t.syncClasses()
 	return t
 }
 
+func (t *baseTag) Article() Tag {
+	return addChild(t, t.htmlGen.Article())
+}
+
+func (t *baseTag) ArticleClasses(classes ...string) Tag {
+	return addChild(t, t.htmlGen.ArticleClasses(classes...))
+}
+
+func (t *baseTag) ArticleId(id string) Tag {
+	return addChild(t, t.htmlGen.ArticleId(id))
+}
+
+func (t *baseTag) ArticleIdClasses(id string, classes ...string) Tag {
+	return addChild(t, t.htmlGen.ArticleIdClasses(id, classes...))
+}
+
+func (t *baseTag) Aside() Tag {
+	return addChild(t, t.htmlGen.Aside())
+}
+
+func (t *baseTag) AsideClasses(classes ...string) Tag {
+	return addChild(t, t.htmlGen.AsideClasses(classes...))
+}
+
+func (t *baseTag) AsideId(id string) Tag {
+	return addChild(t, t.htmlGen.AsideId(id))
+}
+
+func (t *baseTag) AsideIdClasses(id string, classes ...string) Tag {
+	return addChild(t, t.htmlGen.AsideIdClasses(id, classes...))
+}
+
 func (t *baseTag) Assign(tag *Tag) Tag {
 	*tag = t
 	return t
 }
 
-func (t *baseTag) A(href string) Tag {
-	return addChild(t, t.htmlGen.A(href))
+func (t *baseTag) A(href string, options ...*AOptions) Tag {
+	return addChild(t, t.htmlGen.A(href, options...))
 }
 
 func (t *baseTag) Abbr(title ...string) Tag {
@@ -94,10 +140,43 @@ func (t *baseTag) Address() Tag {
 	return addChild(t, t.htmlGen.Address())
 }
 
+func (t *baseTag) AttachShadow(mode ShadowRootMode, options ...*TemplateOptions) (Tag, error) {
+	if len(mode) == 0 || !t.isShadowHost() {
+		return nil, ErrInvalidShadowRoot
+	}
+	if shadow := t.shadowRoot(); shadow != nil {
+		return shadow, nil
+	}
+
+	o := TemplateOptions{}
+	if len(options) > 0 {
+		o = *options[0]
+	}
+	o.ShadowRootMode = mode
+
+	newTag := t.htmlGen.Template(&o)
+	// The shadow root must be the first child of its host.
+	t.children = append([]tagWriter{newTag}, t.children...)
+	newTag.setParent(t)
+	return newTag, nil
+}
+
+func (t *baseTag) Audio(options ...*AudioOptions) Tag {
+	return addChild(t, t.htmlGen.Audio(options...))
+}
+
 func (t *baseTag) B() Tag {
 	return addChild(t, t.htmlGen.B())
 }
 
+func (t *baseTag) Bdi() Tag {
+	return addChild(t, t.htmlGen.Bdi())
+}
+
+func (t *baseTag) Bdo(dir Dir) Tag {
+	return addChild(t, t.htmlGen.Bdo(dir))
+}
+
 func (t *baseTag) Blockquote() Tag {
 	return addChild(t, t.htmlGen.Blockquote())
 }
@@ -116,6 +195,12 @@ func (t *baseTag) Button() Tag {
 	return addChild(t, t.htmlGen.Button())
 }
 
+func (t *baseTag) Classes() []string {
+	result := make([]string, len(t.classes))
+	copy(result, t.classes)
+	return result
+}
+
 func (t *baseTag) Canvas(options ...*CanvasOptions) Tag {
 	return addChild(t, t.htmlGen.Canvas(options...))
 }
@@ -150,9 +235,9 @@ func (t *baseTag) Comment() Tag {
 
 func (t *baseTag) Copy() Tag {
 	if !t.isCacheClean {
-		tagStr := tagTypeStringMap[t.tagType]
+		tagStr := t.tagStr()
 		var err error
-		if t.cacheOpen, err = t.renderCacheOpen(tagStr); err == nil {
+		if t.cacheOpen, err = t.renderCacheOpen(tagStr, t.cacheMode); err == nil {
 			t.isCacheClean = true
 		}
 		// Otherwise, leave the cache dirty.
@@ -160,22 +245,56 @@ func (t *baseTag) Copy() Tag {
 
 	return &baseTag{
 		tagType:      t.tagType,
+		name:         t.name,
 		attrs:        copyAttrs(t.attrs),
 		customAttrs:  copyCustomAttrs(t.customAttrs),
+		classes:      copyClasses(t.classes),
+		styles:       copyStyles(t.styles),
+		srcdoc:       t.srcdoc,
 		children:     make([]tagWriter, 0),
 		isCacheClean: t.isCacheClean,
+		cacheMode:    t.cacheMode,
 		cacheOpen:    t.cacheOpen,
 	}
 }
 
+func (t *baseTag) Data(key string) string {
+	// "" returned by default.
+	name, ok := dataAttrName(key)
+	if !ok {
+		return ""
+	}
+	return t.customAttrs[name]
+}
+
 func (t *baseTag) Datalist() Tag {
 	return addChild(t, t.htmlGen.Datalist())
 }
 
+func (t *baseTag) DataValue(value string) Tag {
+	return addChild(t, t.htmlGen.DataValue(value))
+}
+
+func (t *baseTag) Del(options ...*EditOptions) Tag {
+	return addChild(t, t.htmlGen.Del(options...))
+}
+
+func (t *baseTag) Details(open bool) *DetailsTag {
+	newTag := t.htmlGen.Details(open)
+	addChild(t, newTag)
+	return newTag
+}
+
 func (t *baseTag) Dfn() Tag {
 	return addChild(t, t.htmlGen.Dfn())
 }
 
+func (t *baseTag) Dialog(open, modal bool) *DialogTag {
+	newTag := t.htmlGen.Dialog(open, modal)
+	addChild(t, newTag)
+	return newTag
+}
+
 func (t *baseTag) Div() Tag {
 	return addChild(t, t.htmlGen.Div())
 }
@@ -204,10 +323,58 @@ func (t *baseTag) Dt() Tag {
 	return addChild(t, t.htmlGen.Dt())
 }
 
+func (t *baseTag) Element(name string) (Tag, error) {
+	newTag, err := t.htmlGen.Element(name)
+	if err != nil {
+		return nil, err
+	}
+	return addChild(t, newTag), nil
+}
+
 func (t *baseTag) Em() Tag {
 	return addChild(t, t.htmlGen.Em())
 }
 
+func (t *baseTag) Embed(src, embedType string, options ...*EmbedOptions) Tag {
+	return addChild(t, t.htmlGen.Embed(src, embedType, options...))
+}
+
+func (t *baseTag) Fieldset(options ...*FieldsetOptions) Tag {
+	return addChild(t, t.htmlGen.Fieldset(options...))
+}
+
+func (t *baseTag) Figcaption() Tag {
+	return addChild(t, t.htmlGen.Figcaption())
+}
+
+func (t *baseTag) FigcaptionClasses(classes ...string) Tag {
+	return addChild(t, t.htmlGen.FigcaptionClasses(classes...))
+}
+
+func (t *baseTag) FigcaptionId(id string) Tag {
+	return addChild(t, t.htmlGen.FigcaptionId(id))
+}
+
+func (t *baseTag) FigcaptionIdClasses(id string, classes ...string) Tag {
+	return addChild(t, t.htmlGen.FigcaptionIdClasses(id, classes...))
+}
+
+func (t *baseTag) Figure() Tag {
+	return addChild(t, t.htmlGen.Figure())
+}
+
+func (t *baseTag) FigureClasses(classes ...string) Tag {
+	return addChild(t, t.htmlGen.FigureClasses(classes...))
+}
+
+func (t *baseTag) FigureId(id string) Tag {
+	return addChild(t, t.htmlGen.FigureId(id))
+}
+
+func (t *baseTag) FigureIdClasses(id string, classes ...string) Tag {
+	return addChild(t, t.htmlGen.FigureIdClasses(id, classes...))
+}
+
 func (t *baseTag) Footer() Tag {
 	return addChild(t, t.htmlGen.Footer())
 }
@@ -216,6 +383,11 @@ func (t *baseTag) Form(options ...*FormOptions) Tag {
 	return addChild(t, t.htmlGen.Form(options...))
 }
 
+func (t *baseTag) getAttribute(key string) (string, bool) {
+	value, ok := t.customAttrs[key]
+	return value, ok
+}
+
 func (t *baseTag) getChildren() []tagWriter {
 	if t.children == nil {
 		return []tagWriter{}
@@ -247,10 +419,51 @@ func (t *baseTag) H6() Tag {
 	return addChild(t, t.htmlGen.H6())
 }
 
+func (t *baseTag) HasClass(class string) bool {
+	for _, c := range t.classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *baseTag) Head() Tag {
 	return addChild(t, t.htmlGen.Head())
 }
 
+func (t *baseTag) Header() Tag {
+	return addChild(t, t.htmlGen.Header())
+}
+
+func (t *baseTag) HeaderClasses(classes ...string) Tag {
+	return addChild(t, t.htmlGen.HeaderClasses(classes...))
+}
+
+func (t *baseTag) HeaderId(id string) Tag {
+	return addChild(t, t.htmlGen.HeaderId(id))
+}
+
+func (t *baseTag) HeaderIdClasses(id string, classes ...string) Tag {
+	return addChild(t, t.htmlGen.HeaderIdClasses(id, classes...))
+}
+
+func (t *baseTag) Hgroup() Tag {
+	return addChild(t, t.htmlGen.Hgroup())
+}
+
+func (t *baseTag) HgroupClasses(classes ...string) Tag {
+	return addChild(t, t.htmlGen.HgroupClasses(classes...))
+}
+
+func (t *baseTag) HgroupId(id string) Tag {
+	return addChild(t, t.htmlGen.HgroupId(id))
+}
+
+func (t *baseTag) HgroupIdClasses(id string, classes ...string) Tag {
+	return addChild(t, t.htmlGen.HgroupIdClasses(id, classes...))
+}
+
 func (t *baseTag) Hide(isHidden bool) {
 	t.hidden = isHidden
 }
@@ -268,10 +481,27 @@ func (t *baseTag) Id() string {
 	return t.attrs[kAttrId]
 }
 
+func (t *baseTag) Iframe(src string, options ...*IframeOptions) Tag {
+	return addChild(t, t.htmlGen.Iframe(src, options...))
+}
+
 func (t *baseTag) Img(src, alt string, options ...*ImgOptions) Tag {
 	return addChild(t, t.htmlGen.Img(src, alt, options...))
 }
 
+// Returns the XHTML namespace declaration that is implied for <html> in
+// RenderModeXml unless an xmlns attribute has been assigned, or the empty
+// string.
+func (t *baseTag) impliedXmlns(mode RenderMode) string {
+	if mode != RenderModeXml || t.tagType != kTagTypeHtml {
+		return ""
+	}
+	if _, ok := t.customAttrs["xmlns"]; ok {
+		return ""
+	}
+	return `xmlns="` + NamespaceXhtml + `"`
+}
+
 func (t *baseTag) Input(inputType InputType, options ...*InputOptions) *InputTag {
 	newTag := t.htmlGen.Input(inputType, options...)
 	t.children = append(t.children, newTag)
@@ -284,10 +514,33 @@ func (t *baseTag) InputTypeNameValue(inputType InputType, name, value string) *I
 	return newTag
 }
 
+func (t *baseTag) Ins(options ...*EditOptions) Tag {
+	return addChild(t, t.htmlGen.Ins(options...))
+}
+
+// Returns true if t's children should be written as a CDATA section.
+func (t *baseTag) isCdata(ctx *renderContext) bool {
+	if ctx.mode != RenderModeXml || len(t.children) == 0 {
+		return false
+	}
+	return t.tagType == kTagTypeScript || t.tagType == kTagTypeStyle
+}
+
 func (t *baseTag) isHidden() bool {
 	return t.hidden
 }
 
+// Returns true if a shadow root may be attached to t, which must be a custom
+// element or one of shadowHostNames.
+func (t *baseTag) isShadowHost() bool {
+	if t.tagType == kTagTypeCustom {
+		// Foreign elements, such as those of SVG, are never custom
+		// elements, which have a hyphen.
+		return strings.Contains(t.name, "-")
+	}
+	return shadowHostNames[t.tagStr()]
+}
+
 func (t *baseTag) Kbd() Tag {
 	return addChild(t, t.htmlGen.Kbd())
 }
@@ -296,6 +549,10 @@ func (t *baseTag) Label(options ...*LabelOptions) Tag {
 	return addChild(t, t.htmlGen.Label(options...))
 }
 
+func (t *baseTag) Legend(text ...string) Tag {
+	return addChild(t, t.htmlGen.Legend(text...))
+}
+
 func (t *baseTag) Li() Tag {
 	return addChild(t, t.htmlGen.Li())
 }
@@ -304,36 +561,116 @@ func (t *baseTag) Link(rel string, options ...*LinkOptions) Tag {
 	return addChild(t, t.htmlGen.Link(rel, options...))
 }
 
+func (t *baseTag) Main() Tag {
+	return addChild(t, t.htmlGen.Main())
+}
+
+func (t *baseTag) MainClasses(classes ...string) Tag {
+	return addChild(t, t.htmlGen.MainClasses(classes...))
+}
+
+func (t *baseTag) MainId(id string) Tag {
+	return addChild(t, t.htmlGen.MainId(id))
+}
+
+func (t *baseTag) MainIdClasses(id string, classes ...string) Tag {
+	return addChild(t, t.htmlGen.MainIdClasses(id, classes...))
+}
+
+func (t *baseTag) Mark() Tag {
+	return addChild(t, t.htmlGen.Mark())
+}
+
+func (t *baseTag) Math(options ...*MathOptions) *MathTag {
+	newTag := t.htmlGen.Math(options...)
+	addChild(t, newTag)
+	return newTag
+}
+
+func (t *baseTag) Menu() Tag {
+	return addChild(t, t.htmlGen.Menu())
+}
+
 func (t *baseTag) Meta(name, content string, options ...*MetaOptions) Tag {
 	return addChild(t, t.htmlGen.Meta(name, content, options...))
 }
 
+func (t *baseTag) Meter(options ...*MeterOptions) Tag {
+	return addChild(t, t.htmlGen.Meter(options...))
+}
+
+func (t *baseTag) Nav() Tag {
+	return addChild(t, t.htmlGen.Nav())
+}
+
+func (t *baseTag) NavClasses(classes ...string) Tag {
+	return addChild(t, t.htmlGen.NavClasses(classes...))
+}
+
+func (t *baseTag) NavId(id string) Tag {
+	return addChild(t, t.htmlGen.NavId(id))
+}
+
+func (t *baseTag) NavIdClasses(id string, classes ...string) Tag {
+	return addChild(t, t.htmlGen.NavIdClasses(id, classes...))
+}
+
 func (t *baseTag) NoScript() Tag {
 	return addChild(t, t.htmlGen.NoScript())
 }
 
+func (t *baseTag) Object(options ...*ObjectOptions) Tag {
+	return addChild(t, t.htmlGen.Object(options...))
+}
+
 func (t *baseTag) Ol() Tag {
 	return addChild(t, t.htmlGen.Ol())
 }
 
+func (t *baseTag) Optgroup(label string, options ...*OptgroupOptions) *OptgroupTag {
+	newTag := t.htmlGen.Optgroup(label, options...)
+	addChild(t, newTag)
+	return newTag
+}
+
 func (t *baseTag) Option(options ...*OptionOptions) *OptionTag {
 	newTag := t.htmlGen.Option(options...)
 	t.children = append(t.children, newTag)
 	return newTag
 }
 
+func (t *baseTag) Output(options ...*OutputOptions) Tag {
+	return addChild(t, t.htmlGen.Output(options...))
+}
+
 func (t *baseTag) P() Tag {
 	return addChild(t, t.htmlGen.P())
 }
 
+func (t *baseTag) Param(name, value string) Tag {
+	return addChild(t, t.htmlGen.Param(name, value))
+}
+
 func (t *baseTag) Parent() Tag {
 	return t.parent
 }
 
+func (t *baseTag) Picture(src, alt string, sources []*SourceOptions, options ...*ImgOptions) Tag {
+	return addChild(t, t.htmlGen.Picture(src, alt, sources, options...))
+}
+
 func (t *baseTag) Pre() Tag {
 	return addChild(t, t.htmlGen.Pre())
 }
 
+func (t *baseTag) Progress(options ...*ProgressOptions) Tag {
+	return addChild(t, t.htmlGen.Progress(options...))
+}
+
+func (t *baseTag) Q(cite ...string) Tag {
+	return addChild(t, t.htmlGen.Q(cite...))
+}
+
 func (t *baseTag) RemoveAttribute(key string) Tag {
 	delete(t.customAttrs, key)
 	t.isCacheClean = false
@@ -348,6 +685,58 @@ func (t *baseTag) RemoveAttributes(keys ...string) Tag {
 	return t
 }
 
+func (t *baseTag) RemoveClass(classes ...string) Tag {
+	removed := splitClasses(classes)
+	if len(removed) == 0 {
+		return t
+	}
+
+	newClasses := t.classes[:0]
+	for _, c := range t.classes {
+		keep := true
+		for _, r := range removed {
+			if c == r {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			newClasses = append(newClasses, c)
+		}
+	}
+	t.classes = newClasses
+
+	t.syncClasses()
+	return t
+}
+
+func (t *baseTag) RemoveData(key string) Tag {
+	name, ok := dataAttrName(key)
+	if !ok {
+		return t
+	}
+	return t.RemoveAttribute(name)
+}
+
+func (t *baseTag) RemoveTabindex() Tag {
+	return t.setAttr(kAttrTabindex, "")
+}
+
+func (t *baseTag) RemoveStyle(props ...string) Tag {
+	for _, prop := range props {
+		prop = cssPropertyName(prop)
+		for ii, p := range t.styles {
+			if p.name == prop {
+				t.styles = append(t.styles[:ii], t.styles[ii+1:]...)
+				break
+			}
+		}
+	}
+
+	t.syncStyles()
+	return t
+}
+
 func (t *baseTag) RemoveChildren() Tag {
 	t.children = t.children[:0]
 	return t
@@ -377,20 +766,52 @@ func (t *baseTag) RemoveParent() Tag {
 	return t
 }
 
-// Renders the opening tag with attributes to a string.
-func (t *baseTag) renderCacheOpen(tagStr string) (result string, err error) {
+// Renders the opening tag with attributes, excluding its end (e.g., '>'),
+// to a string.
+func (t *baseTag) renderCacheOpen(tagStr string, mode RenderMode) (result string, err error) {
 	tmp := new(bytes.Buffer)
-	if _, err = t.writeOpenTagLead(tmp, tagStr); err != nil {
-		return
-	}
-	// Finish the open tag.
-	if _, err = writeRune(tmp, '>'); err != nil {
+	if _, err = t.writeOpenTagLead(tmp, tagStr, mode); err != nil {
 		return
 	}
 	result = tmp.String()
 	return
 }
 
+// Returns the srcdoc attribute, which renders t.srcdoc with the environment
+// and mode of ctx, or the empty string if t has no srcdoc.
+func (t *baseTag) renderSrcdoc(ctx *renderContext) (string, error) {
+	if t.srcdoc == nil {
+		return "", nil
+	}
+
+	doc := new(bytes.Buffer)
+	docCtx := newRenderContext(&RenderOptions{Env: ctx.env, Mode: ctx.mode})
+	if _, err := t.srcdoc.write(doc, docCtx); err != nil {
+		return "", err
+	}
+
+	// Writing to a buffer cannot fail.
+	buf := new(bytes.Buffer)
+	writeKeyIdValue(buf, kAttrSrcdoc, html.EscapeString(doc.String()), ctx.mode)
+	return buf.String(), nil
+}
+
+func (t *baseTag) Rp() Tag {
+	return addChild(t, t.htmlGen.Rp())
+}
+
+func (t *baseTag) Rt() Tag {
+	return addChild(t, t.htmlGen.Rt())
+}
+
+func (t *baseTag) Ruby() Tag {
+	return addChild(t, t.htmlGen.Ruby())
+}
+
+func (t *baseTag) S() Tag {
+	return addChild(t, t.htmlGen.S())
+}
+
 func (t *baseTag) Samp() Tag {
 	return addChild(t, t.htmlGen.Samp())
 }
@@ -399,8 +820,44 @@ func (t *baseTag) Script(optScriptType ...string) Tag {
 	return addChild(t, t.htmlGen.Script(optScriptType...))
 }
 
-func (t *baseTag) ScriptSrc(scriptType, src string) Tag {
-	return addChild(t, t.htmlGen.ScriptSrc(scriptType, src))
+func (t *baseTag) ScriptImportMap(m *ImportMap, options ...*ScriptOptions) Tag {
+	return addChild(t, t.htmlGen.ScriptImportMap(m, options...))
+}
+
+func (t *baseTag) ScriptSrc(scriptType, src string, options ...*ScriptOptions) Tag {
+	return addChild(t, t.htmlGen.ScriptSrc(scriptType, src, options...))
+}
+
+func (t *baseTag) Search() Tag {
+	return addChild(t, t.htmlGen.Search())
+}
+
+func (t *baseTag) SearchClasses(classes ...string) Tag {
+	return addChild(t, t.htmlGen.SearchClasses(classes...))
+}
+
+func (t *baseTag) SearchId(id string) Tag {
+	return addChild(t, t.htmlGen.SearchId(id))
+}
+
+func (t *baseTag) SearchIdClasses(id string, classes ...string) Tag {
+	return addChild(t, t.htmlGen.SearchIdClasses(id, classes...))
+}
+
+func (t *baseTag) Section() Tag {
+	return addChild(t, t.htmlGen.Section())
+}
+
+func (t *baseTag) SectionClasses(classes ...string) Tag {
+	return addChild(t, t.htmlGen.SectionClasses(classes...))
+}
+
+func (t *baseTag) SectionId(id string) Tag {
+	return addChild(t, t.htmlGen.SectionId(id))
+}
+
+func (t *baseTag) SectionIdClasses(id string, classes ...string) Tag {
+	return addChild(t, t.htmlGen.SectionIdClasses(id, classes...))
 }
 
 func (t *baseTag) Select(options ...*SelectOptions) *SelectTag {
@@ -409,6 +866,19 @@ func (t *baseTag) Select(options ...*SelectOptions) *SelectTag {
 	return newTag
 }
 
+// Sets the boolean attribute specified by keyId if on is true; otherwise,
+// the attribute is cleared.  This returns t.
+func (t *baseTag) setBoolAttr(keyId int, on bool) Tag {
+	if on {
+		t.attrs[keyId] = ""
+	} else {
+		delete(t.attrs, keyId)
+	}
+
+	t.isCacheClean = false
+	return t
+}
+
 // Sets the attribute specified by keyId to value.  If value is empty, the
 // attribute will be cleared.  This returns t.
 func (t *baseTag) setAttr(keyId int, value string) Tag {
@@ -422,6 +892,49 @@ func (t *baseTag) setAttr(keyId int, value string) Tag {
 	return t
 }
 
+func (t *baseTag) SetAccesskey(keys string) Tag {
+	return t.setAttr(kAttrAccesskey, keys)
+}
+
+func (t *baseTag) SetAria(name, value string) Tag {
+	name = strings.ToLower(name)
+	if !reAriaName.MatchString(name) {
+		// Invalid names are ignored, since they would otherwise allow
+		// arbitrary attribute names.
+		return t
+	}
+
+	key := kAttrPrefixAria + name
+	if len(value) == 0 {
+		return t.RemoveAttribute(key)
+	}
+	return t.SetAttribute(key, value)
+}
+
+func (t *baseTag) SetAriaDescribedby(ids ...string) Tag {
+	return t.SetAria("describedby", strings.Join(ids, " "))
+}
+
+func (t *baseTag) SetAriaExpanded(expanded bool) Tag {
+	return t.SetAria("expanded", formatBool(expanded))
+}
+
+func (t *baseTag) SetAriaHidden(hidden bool) Tag {
+	if !hidden {
+		// aria-hidden="false" is discouraged, so simply clear it.
+		return t.SetAria("hidden", "")
+	}
+	return t.SetAria("hidden", kHtmlTrue)
+}
+
+func (t *baseTag) SetAriaLabel(label string) Tag {
+	return t.SetAria("label", label)
+}
+
+func (t *baseTag) SetAutofocus(autofocus bool) Tag {
+	return t.setBoolAttr(kAttrAutofocus, autofocus)
+}
+
 func (t *baseTag) SetAttribute(key, value string) Tag {
 	t.customAttrs[key] = value
 	t.isCacheClean = false
@@ -446,89 +959,221 @@ func (t *baseTag) SetClass(classes ...string) Tag {
 }
 
 func (t *baseTag) SetClasses(classes []string) Tag {
-	if classes == nil || len(classes) == 0 {
-		delete(t.attrs, kAttrClass)
-	} else {
-		classesStr := strings.Join(classes, " ")
-		t.attrs[kAttrClass] = classesStr
+	t.classes = t.classes[:0]
+	return t.AddClasses(classes)
+}
+
+func (t *baseTag) SetContentEditable(value ContentEditable) Tag {
+	return t.setAttr(kAttrContenteditable, string(value))
+}
+
+func (t *baseTag) SetData(key, value string) Tag {
+	name, ok := dataAttrName(key)
+	if !ok {
+		// Invalid keys are ignored, since they would otherwise allow
+		// arbitrary attribute names.
+		return t
 	}
+	return t.SetAttribute(name, value)
+}
 
-	t.isCacheClean = false
-	return t
+func (t *baseTag) SetDataJSON(key string, v interface{}) error {
+	if _, ok := dataAttrName(key); !ok {
+		return ErrInvalidDataKey
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// Attribute values are written verbatim, and JSON always contains
+	// quotes.
+	t.SetData(key, html.EscapeString(string(encoded)))
+	return nil
+}
+
+func (t *baseTag) SetDir(dir Dir) Tag {
+	return t.setAttr(kAttrDir, string(dir))
+}
+
+func (t *baseTag) SetDraggable(value Draggable) Tag {
+	return t.setAttr(kAttrDraggable, string(value))
+}
+
+func (t *baseTag) SetHidden(hidden bool) Tag {
+	return t.setBoolAttr(kAttrHidden, hidden)
 }
 
 func (t *baseTag) SetId(id string) Tag {
 	return t.setAttr(kAttrId, id)
 }
 
+func (t *baseTag) SetInert(inert bool) Tag {
+	return t.setBoolAttr(kAttrInert, inert)
+}
+
+func (t *baseTag) SetLang(lang string) Tag {
+	return t.setAttr(kAttrLang, lang)
+}
+
+func (t *baseTag) SetOn(event EventName, script string) Tag {
+	// The handler is decoded by the HTML parser before it is evaluated,
+	// so escaping preserves the script while keeping it inside the
+	// attribute.
+	return t.setOn(event, html.EscapeString(script))
+}
+
+// Sets the handler for event to value, which is written verbatim.
+func (t *baseTag) setOn(event EventName, value string) Tag {
+	if !reEventName.MatchString(string(event)) {
+		// Invalid event names are ignored, since they would otherwise
+		// allow arbitrary attribute names.
+		return t
+	}
+
+	key := kAttrPrefixEvent + string(event)
+	if len(value) == 0 {
+		return t.RemoveAttribute(key)
+	}
+	return t.SetAttribute(key, value)
+}
+
 func (t *baseTag) SetOnblur(script string) Tag {
-	return t.setAttr(kAttrOnblur, script)
+	return t.setOn(EventBlur, script)
 }
 
 func (t *baseTag) SetOnclick(script string) Tag {
-	return t.setAttr(kAttrOnclick, script)
+	return t.setOn(EventClick, script)
 }
 
 func (t *baseTag) SetOndblclick(script string) Tag {
-	return t.setAttr(kAttrOndblclick, script)
+	return t.setOn(EventDblclick, script)
 }
 
 func (t *baseTag) SetOnchange(script string) Tag {
-	return t.setAttr(kAttrOnchange, script)
+	return t.setOn(EventChange, script)
 }
 
 func (t *baseTag) SetOnfocus(script string) Tag {
-	return t.setAttr(kAttrOnfocus, script)
+	return t.setOn(EventFocus, script)
 }
 
 func (t *baseTag) SetOnselect(script string) Tag {
-	return t.setAttr(kAttrOnselect, script)
+	return t.setOn(EventSelect, script)
 }
 
 func (t *baseTag) SetOnsubmit(script string) Tag {
-	return t.setAttr(kAttrOnsubmit, script)
+	return t.setOn(EventSubmit, script)
 }
 
 func (t *baseTag) SetOnkeydown(script string) Tag {
-	return t.setAttr(kAttrOnkeydown, script)
+	return t.setOn(EventKeydown, script)
 }
 
 func (t *baseTag) SetOnkeypress(script string) Tag {
-	return t.setAttr(kAttrOnkeypress, script)
+	return t.setOn(EventKeypress, script)
 }
 
 func (t *baseTag) SetOnkeyup(script string) Tag {
-	return t.setAttr(kAttrOnkeyup, script)
+	return t.setOn(EventKeyup, script)
 }
 
 func (t *baseTag) SetOnmousedown(script string) Tag {
-	return t.setAttr(kAttrOnmousedown, script)
+	return t.setOn(EventMousedown, script)
 }
 
 func (t *baseTag) SetOnmousemove(script string) Tag {
-	return t.setAttr(kAttrOnmousemove, script)
+	return t.setOn(EventMousemove, script)
 }
 
 func (t *baseTag) SetOnmouseout(script string) Tag {
-	return t.setAttr(kAttrOnmouseout, script)
+	return t.setOn(EventMouseout, script)
 }
 
 func (t *baseTag) SetOnmouseover(script string) Tag {
-	return t.setAttr(kAttrOnmouseover, script)
+	return t.setOn(EventMouseover, script)
 }
 
 func (t *baseTag) SetOnmouseup(script string) Tag {
-	return t.setAttr(kAttrOnmouseup, script)
+	return t.setOn(EventMouseup, script)
+}
+
+func (t *baseTag) SetRole(role string) Tag {
+	return t.setAttr(kAttrRole, role)
 }
 
 func (t *baseTag) setParent(parent Tag) {
 	t.parent = parent
 }
 
+func (t *baseTag) SetSpellcheck(value Spellcheck) Tag {
+	return t.setAttr(kAttrSpellcheck, string(value))
+}
+
+func (t *baseTag) SetStyle(prop, value string) Tag {
+	prop = cssPropertyName(prop)
+	if !reCssProperty.MatchString(prop) {
+		// Invalid property names are ignored, since they cannot be
+		// escaped.
+		return t
+	}
+
+	if len(value) == 0 {
+		return t.RemoveStyle(prop)
+	}
+
+	if !isCssValue(value) {
+		// Values that could end the declaration are ignored.
+		return t
+	}
+
+	for ii, p := range t.styles {
+		if p.name == prop {
+			t.styles[ii].value = value
+			t.syncStyles()
+			return t
+		}
+	}
+
+	t.styles = append(t.styles, styleProperty{name: prop, value: value})
+	t.syncStyles()
+	return t
+}
+
+func (t *baseTag) SetTabindex(index int) Tag {
+	return t.setAttr(kAttrTabindex, strconv.Itoa(index))
+}
+
 func (t *baseTag) SetTitle(title string) Tag {
 	return t.setAttr(kAttrTitle, title)
 }
 
+func (t *baseTag) SetTranslate(value Translate) Tag {
+	return t.setAttr(kAttrTranslate, string(value))
+}
+
+// Returns the declarative shadow root of t, or nil if there is none.
+func (t *baseTag) shadowRoot() *baseTag {
+	if len(t.children) == 0 {
+		return nil
+	}
+	first, ok := t.children[0].(*baseTag)
+	if !ok || first.tagType != kTagTypeTemplate {
+		return nil
+	}
+	if _, ok := first.attrs[kAttrShadowrootmode]; !ok {
+		return nil
+	}
+	return first
+}
+
+func (t *baseTag) Slot(name string) Tag {
+	return addChild(t, t.htmlGen.Slot(name))
+}
+
+func (t *baseTag) Source(options ...*SourceOptions) Tag {
+	return addChild(t, t.htmlGen.Source(options...))
+}
+
 func (t *baseTag) Span() Tag {
 	return addChild(t, t.htmlGen.Span())
 }
@@ -553,10 +1198,63 @@ func (t *baseTag) Strong() Tag {
 	return addChild(t, t.htmlGen.Strong())
 }
 
+func (t *baseTag) Styles() map[string]string {
+	result := make(map[string]string, len(t.styles))
+	for _, p := range t.styles {
+		result[p.name] = p.value
+	}
+	return result
+}
+
 func (t *baseTag) Style(options ...*StyleOptions) Tag {
 	return addChild(t, t.htmlGen.Style(options...))
 }
 
+func (t *baseTag) Sub() Tag {
+	return addChild(t, t.htmlGen.Sub())
+}
+
+func (t *baseTag) Summary(text ...string) Tag {
+	return addChild(t, t.htmlGen.Summary(text...))
+}
+
+func (t *baseTag) Sup() Tag {
+	return addChild(t, t.htmlGen.Sup())
+}
+
+func (t *baseTag) Svg(options ...*SvgOptions) *SvgTag {
+	newTag := t.htmlGen.Svg(options...)
+	addChild(t, newTag)
+	return newTag
+}
+
+// Updates the class attribute to reflect t.classes.
+func (t *baseTag) syncClasses() {
+	if len(t.classes) == 0 {
+		delete(t.attrs, kAttrClass)
+	} else {
+		t.attrs[kAttrClass] = strings.Join(t.classes, " ")
+	}
+
+	t.isCacheClean = false
+}
+
+// Updates the style attribute to reflect t.styles.
+func (t *baseTag) syncStyles() {
+	if len(t.styles) == 0 {
+		delete(t.attrs, kAttrStyle)
+	} else {
+		props := make([]string, len(t.styles))
+		for ii, p := range t.styles {
+			// Attribute values are written verbatim.
+			props[ii] = p.name + ": " + html.EscapeString(p.value)
+		}
+		t.attrs[kAttrStyle] = strings.Join(props, "; ")
+	}
+
+	t.isCacheClean = false
+}
+
 func (t *baseTag) T(text ...string) *TextTag {
 	newTag := t.htmlGen.T(text...)
 	newTag.parent = t
@@ -568,6 +1266,14 @@ func (t *baseTag) Table(options ...*TableOptions) Tag {
 	return addChild(t, t.htmlGen.Table(options...))
 }
 
+// Returns the element name of t, which is empty for null and comment tags.
+func (t *baseTag) tagStr() string {
+	if t.tagType < kTagTypeMAXCOUNT {
+		return tagTypeStringMap[t.tagType]
+	}
+	return t.name
+}
+
 func (t *baseTag) Tbody() Tag {
 	return addChild(t, t.htmlGen.Tbody())
 }
@@ -576,6 +1282,10 @@ func (t *baseTag) Td(options ...*TdOptions) Tag {
 	return addChild(t, t.htmlGen.Td(options...))
 }
 
+func (t *baseTag) Template(options ...*TemplateOptions) Tag {
+	return addChild(t, t.htmlGen.Template(options...))
+}
+
 func (t *baseTag) Textarea(rows, cols int, options ...*TextareaOptions) Tag {
 	return addChild(t, t.htmlGen.Textarea(rows, cols, options...))
 }
@@ -592,6 +1302,10 @@ func (t *baseTag) Thead() Tag {
 	return addChild(t, t.htmlGen.Thead())
 }
 
+func (t *baseTag) Time(tm time.Time, layout string) Tag {
+	return addChild(t, t.htmlGen.Time(tm, layout))
+}
+
 func (t *baseTag) Title() Tag {
 	return addChild(t, t.htmlGen.Title())
 }
@@ -600,6 +1314,32 @@ func (t *baseTag) Tr() Tag {
 	return addChild(t, t.htmlGen.Tr())
 }
 
+func (t *baseTag) ToggleClass(class string, on bool) Tag {
+	if on {
+		return t.AddClass(class)
+	}
+	return t.RemoveClass(class)
+}
+
+func (t *baseTag) ToggleClasses(classes ClassMap) Tag {
+	// Apply in sorted order so that added classes are ordered
+	// deterministically.
+	keys := make([]string, 0, len(classes))
+	for k := range classes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		t.ToggleClass(k, classes[k])
+	}
+	return t
+}
+
+func (t *baseTag) Track(src string, options ...*TrackOptions) Tag {
+	return addChild(t, t.htmlGen.Track(src, options...))
+}
+
 func (t *baseTag) TUnsafe(text ...string) *TextTag {
 	newTag := t.htmlGen.TUnsafe(text...)
 	newTag.parent = t
@@ -649,35 +1389,88 @@ func (t *baseTag) Var() Tag {
 	return addChild(t, t.htmlGen.Var())
 }
 
-func (t *baseTag) write(writer io.Writer, env ...Environment) (n int, err error) {
+func (t *baseTag) Video(options ...*VideoOptions) Tag {
+	return addChild(t, t.htmlGen.Video(options...))
+}
+
+func (t *baseTag) VoidElement(name string) (Tag, error) {
+	newTag, err := t.htmlGen.VoidElement(name)
+	if err != nil {
+		return nil, err
+	}
+	return addChild(t, newTag), nil
+}
+
+func (t *baseTag) Wbr() Tag {
+	return addChild(t, t.htmlGen.Wbr())
+}
+
+func (t *baseTag) write(writer io.Writer, ctx *renderContext) (n int, err error) {
+	omitEndTag := ctx.omitEndTag
+	ctx.omitEndTag = false
 	if t.hidden {
 		return
 	}
 
-	tagStr := tagTypeStringMap[t.tagType]
+	tagStr := t.tagStr()
 
 	// Write the opening tag.
-	if !t.isCacheClean {
-		if t.cacheOpen, err = t.renderCacheOpen(tagStr); err != nil {
-			return
-		}
-		t.isCacheClean = true
+	if count, err := t.writeOpenTagLeadCached(writer, tagStr, ctx); err != nil {
+		return n, err
+	} else {
+		n += count
 	}
-	if count, err := io.WriteString(writer, t.cacheOpen); err != nil {
+	if count, err := writeRune(writer, '>'); err != nil {
 		return n, err
 	} else {
 		n += count
 	}
 
+	// Script and style text is character data in XML.
+	cdata := t.isCdata(ctx)
+	if cdata {
+		if count, err := io.WriteString(writer, "<![CDATA["); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
+		ctx.inCdata = true
+	}
+
+	preformatted := ctx.mode == RenderModeHtmlMinified && preformattedElements[tagStr]
+	if preformatted {
+		ctx.preformatted++
+	}
+
 	// Recursively write all children.
-	for _, childTag := range t.children {
-		if count, err := childTag.write(writer, env...); err != nil {
+	for ii, childTag := range t.children {
+		if ctx.mode == RenderModeHtmlMinified {
+			ctx.omitEndTag = t.canOmitEndTag(ii)
+		}
+		if count, err := childTag.write(writer, ctx); err != nil {
 			return n, err
 		} else {
 			n += count
 		}
 	}
 
+	if preformatted {
+		ctx.preformatted--
+	}
+
+	if cdata {
+		ctx.inCdata = false
+		if count, err := io.WriteString(writer, "]]>"); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
+	}
+
+	if omitEndTag {
+		return
+	}
+
 	// Write the closing tag.
 	if count, err := io.WriteString(writer, "</"); err != nil {
 		return n, err
@@ -697,23 +1490,66 @@ func (t *baseTag) write(writer io.Writer, env ...Environment) (n int, err error)
 	return
 }
 
-// NOTE: currently a newline is introduced if a child tag is hidden.
-func (t *baseTag) writePretty(writer io.Writer, indent int, env ...Environment) (n int, err error) {
+// Writes the opening tag with attributes, excluding its end (e.g., '>'),
+// from the cache.  Attributes are sorted instead if ctx requires it.
+func (t *baseTag) writeOpenTagLeadCached(writer io.Writer, tagStr string, ctx *renderContext) (int, error) {
+	if ctx.overrideAttrs != nil {
+		// Write a shallow copy so that t and its cache are not modified.
+		overridden := *t
+		overridden.customAttrs = copyCustomAttrs(t.customAttrs)
+		for k, v := range ctx.overrideAttrs {
+			overridden.customAttrs[k] = v
+		}
+		overridden.isCacheClean = false
+		ctx.overrideAttrs = nil
+		return overridden.writeOpenTagLeadCached(writer, tagStr, ctx)
+	}
+
+	if ctx.sortAttrs {
+		return t.writeOpenTagLeadSorted(writer, tagStr, -1, ctx)
+	}
+
+	if !t.isCacheClean || t.cacheMode != ctx.mode {
+		cacheOpen, err := t.renderCacheOpen(tagStr, ctx.mode)
+		if err != nil {
+			return 0, err
+		}
+		t.cacheOpen = cacheOpen
+		t.cacheMode = ctx.mode
+		t.isCacheClean = true
+	}
+
+	srcdoc, err := t.renderSrcdoc(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(srcdoc) > 0 {
+		return io.WriteString(writer, t.cacheOpen+" "+srcdoc)
+	}
+	return io.WriteString(writer, t.cacheOpen)
+}
+
+func (t *baseTag) writePretty(writer io.Writer, indent int, ctx *renderContext) (n int, err error) {
 	if t.hidden {
 		return
 	}
 
-	tagStr := tagTypeStringMap[t.tagType]
+	tagStr := t.tagStr()
 
 	// Pretty print with indentation.
-	if count, err := writeIndent(writer, indent); err != nil {
+	if count, err := writeIndent(writer, indent, ctx); err != nil {
 		return n, err
 	} else {
 		n += count
 	}
 
+	if ctx.isCompact(tagStr) {
+		count, err := t.write(writer, ctx)
+		return n + count, err
+	}
+
 	// Write the opening tag.
-	if count, err := t.writeOpenTagLeadSorted(writer, tagStr); err != nil {
+	if count, err := t.writeOpenTagLeadSorted(writer, tagStr, indent, ctx); err != nil {
 		return n, err
 	} else {
 		n += count
@@ -725,7 +1561,30 @@ func (t *baseTag) writePretty(writer io.Writer, indent int, env ...Environment)
 		n += count
 	}
 
-	newIndent := indent + kIndentSpace
+	// Script and style text is character data in XML.
+	cdata := t.isCdata(ctx)
+	if cdata {
+		if count, err := io.WriteString(writer, "<![CDATA["); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
+		ctx.inCdata = true
+	}
+
+	// Inline children are written on the line of the open tag if all
+	// children are inline, or else on a shared line.
+	allInline := true
+	for _, childTag := range t.children {
+		if !childTag.isHidden() && !ctx.isInline(childTag) {
+			allInline = false
+			break
+		}
+	}
+
+	newIndent := indent + 1
+	hasLines := false
+	onInlineLine := allInline
 
 	// Recursively write all children.
 	for _, childTag := range t.children {
@@ -733,28 +1592,65 @@ func (t *baseTag) writePretty(writer io.Writer, indent int, env ...Environment)
 			continue
 		}
 
+		inline := ctx.isInline(childTag)
+		if inline && onInlineLine {
+			if count, err := childTag.write(writer, ctx); err != nil {
+				return n, err
+			} else {
+				n += count
+			}
+			continue
+		}
+
 		// Pretty print with newline between children.
 		if count, err := writeRune(writer, '\n'); err != nil {
 			return n, err
 		} else {
 			n += count
 		}
+		hasLines = true
 
-		if count, err := childTag.writePretty(writer, newIndent, env...); err != nil {
+		if inline {
+			// Start a line of inline content.
+			if count, err := writeIndent(writer, newIndent, ctx); err != nil {
+				return n, err
+			} else {
+				n += count
+			}
+			if count, err := childTag.write(writer, ctx); err != nil {
+				return n, err
+			} else {
+				n += count
+			}
+			onInlineLine = true
+			continue
+		}
+
+		if count, err := childTag.writePretty(writer, newIndent, ctx); err != nil {
 			return n, err
 		} else {
 			n += count
 		}
+		onInlineLine = false
 	}
 
-	if len(t.children) > 0 {
+	if cdata {
+		ctx.inCdata = false
+		if count, err := io.WriteString(writer, "]]>"); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
+	}
+
+	if hasLines {
 		// Separate from children.
 		if count, err := writeRune(writer, '\n'); err != nil {
 			return n, err
 		} else {
 			n += count
 		}
-		if count, err := writeIndent(writer, indent); err != nil {
+		if count, err := writeIndent(writer, indent, ctx); err != nil {
 			return n, err
 		} else {
 			n += count
@@ -773,7 +1669,7 @@ func (t *baseTag) writePretty(writer io.Writer, indent int, env ...Environment)
 
 // Writes the leading part of the opening tag and its attributes, up until
 // the closing ">".
-func (t *baseTag) writeOpenTagLead(writer io.Writer, tagStr string) (n int, err error) {
+func (t *baseTag) writeOpenTagLead(writer io.Writer, tagStr string, mode RenderMode) (n int, err error) {
 	if count, err := writeRune(writer, '<'); err != nil {
 		return n, err
 	} else {
@@ -784,6 +1680,13 @@ func (t *baseTag) writeOpenTagLead(writer io.Writer, tagStr string) (n int, err
 	} else {
 		n += count
 	}
+	if xmlns := t.impliedXmlns(mode); len(xmlns) > 0 {
+		if count, err := io.WriteString(writer, " "+xmlns); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
+	}
 
 	// Write any attributes.
 	for key, value := range t.attrs {
@@ -793,7 +1696,7 @@ func (t *baseTag) writeOpenTagLead(writer io.Writer, tagStr string) (n int, err
 		} else {
 			n += count
 		}
-		if count, err := writeKeyIdValue(writer, key, value); err != nil {
+		if count, err := writeKeyIdValue(writer, key, value, mode); err != nil {
 			return n, err
 		} else {
 			n += count
@@ -807,7 +1710,7 @@ func (t *baseTag) writeOpenTagLead(writer io.Writer, tagStr string) (n int, err
 		} else {
 			n += count
 		}
-		if count, err := writeKeyValue(writer, key, value); err != nil {
+		if count, err := writeKeyValue(writer, key, value, mode); err != nil {
 			return n, err
 		} else {
 			n += count
@@ -817,43 +1720,72 @@ func (t *baseTag) writeOpenTagLead(writer io.Writer, tagStr string) (n int, err
 	return
 }
 
-// Like writeOpenTagLead, only that attributes will be sorted.
-func (t *baseTag) writeOpenTagLeadSorted(writer io.Writer, tagStr string) (n int, err error) {
-	if count, err := writeRune(writer, '<'); err != nil {
+// Like writeOpenTagLead, only that attributes will be sorted.  When pretty
+// printing at the given indent level, attributes are written on separate
+// lines if the open tag would exceed ctx.maxLineWidth.  indent is negative
+// for compact output.
+func (t *baseTag) writeOpenTagLeadSorted(writer io.Writer, tagStr string, indent int, ctx *renderContext) (n int, err error) {
+	lead := "<" + tagStr
+	attrs := t.sortedAttrs(ctx.mode)
+	if srcdoc, err := t.renderSrcdoc(ctx); err != nil {
 		return n, err
-	} else {
-		n += count
+	} else if len(srcdoc) > 0 {
+		attrs = append(attrs, srcdoc)
+		sort.Strings(attrs)
 	}
-	if count, err := io.WriteString(writer, tagStr); err != nil {
+
+	sep := " "
+	if ctx.maxLineWidth > 0 && indent >= 0 {
+		// Include the end of the open tag.
+		width := utf8.RuneCountInString(strings.Repeat(ctx.indent, indent)+lead) + 1
+		for _, attr := range attrs {
+			width += 1 + utf8.RuneCountInString(attr)
+		}
+		if width > ctx.maxLineWidth {
+			sep = "\n" + strings.Repeat(ctx.indent, indent+1)
+		}
+	}
+
+	if count, err := io.WriteString(writer, lead); err != nil {
 		return n, err
 	} else {
 		n += count
 	}
+	for _, attr := range attrs {
+		if count, err := io.WriteString(writer, sep+attr); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
+	}
+	return
+}
+
+// Returns the rendered attributes of t, such as key="value", in sorted
+// order.
+func (t *baseTag) sortedAttrs(mode RenderMode) []string {
+	result := make([]string, 0, len(t.attrs)+len(t.customAttrs)+1)
+	if xmlns := t.impliedXmlns(mode); len(xmlns) > 0 {
+		result = append(result, xmlns)
+	}
+
+	// Writing to a buffer cannot fail.
+	buf := new(bytes.Buffer)
 
 	// The common case doesn't include custom attributes.
 	if len(t.customAttrs) == 0 {
-		sortedKeys := make([]int, len(t.attrs))
-		ii := 0
+		sortedKeys := make([]int, 0, len(t.attrs))
 		for k := range t.attrs {
-			sortedKeys[ii] = k
-			ii++
+			sortedKeys = append(sortedKeys, k)
 		}
 		sort.Ints(sortedKeys)
 
 		for _, key := range sortedKeys {
-			value := t.attrs[key]
-			if count, err := writeRune(writer, ' '); err != nil {
-				return n, err
-			} else {
-				n += count
-			}
-			if count, err := writeKeyIdValue(writer, key, value); err != nil {
-				return n, err
-			} else {
-				n += count
-			}
+			buf.Reset()
+			writeKeyIdValue(buf, key, t.attrs[key], mode)
+			result = append(result, buf.String())
 		}
-		return
+		return result
 	}
 
 	// Otherwise, merge known and custom attrs into a single map.
@@ -874,20 +1806,11 @@ func (t *baseTag) writeOpenTagLeadSorted(writer io.Writer, tagStr string) (n int
 
 	sort.Strings(sortedKeys)
 	for _, key := range sortedKeys {
-		value := attrs[key]
-
-		if count, err := writeRune(writer, ' '); err != nil {
-			return n, err
-		} else {
-			n += count
-		}
-		if count, err := writeKeyValue(writer, key, value); err != nil {
-			return n, err
-		} else {
-			n += count
-		}
+		buf.Reset()
+		writeKeyValue(buf, key, attrs[key], mode)
+		result = append(result, buf.String())
 	}
-	return
+	return result
 }
 
 // Returns newTag.
@@ -896,3 +1819,13 @@ func addChild(t *baseTag, newTag Tag) Tag {
 	newTag.setParent(t)
 	return newTag
 }
+
+// Splits each entry of classes on whitespace, so that "foo bar" is treated
+// as two classes.
+func splitClasses(classes []string) []string {
+	result := make([]string, 0, len(classes))
+	for _, c := range classes {
+		result = append(result, strings.Fields(c)...)
+	}
+	return result
+}
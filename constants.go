@@ -20,80 +20,141 @@ const (
 // Class attributes
 // This must match the order of attrStringMap.
 const (
-	kAttrAcceptCharset = iota
-	kAttrAction        = iota
-	kAttrAlt           = iota
-	kAttrAutocomplete  = iota
-	kAttrAutofocus     = iota
-	kAttrBorder        = iota
-	kAttrCharset       = iota
-	kAttrChecked       = iota
-	kAttrClass         = iota
-	kAttrCols          = iota
-	kAttrColspan       = iota
-	kAttrContent       = iota
-	kAttrDisabled      = iota
-	kAttrEnctype       = iota
-	kAttrFor           = iota
-	kAttrForm          = iota
-	kAttrHeaders       = iota
-	kAttrHeight        = iota
-	kAttrHref          = iota
-	kAttrHreflang      = iota
-	kAttrHttpEquiv     = iota
-	kAttrId            = iota
-	kAttrIsmap         = iota
-	kAttrLabel         = iota
-	kAttrList          = iota
-	kAttrMax           = iota
-	kAttrMaxlength     = iota
-	kAttrMedia         = iota
-	kAttrMethod        = iota
-	kAttrMin           = iota
-	kAttrMultiple      = iota
-	kAttrName          = iota
-	kAttrOnblur        = iota
-	kAttrOnchange      = iota
-	kAttrOnclick       = iota
-	kAttrOndblclick    = iota
-	kAttrOnfocus       = iota
-	kAttrOnload        = iota
-	kAttrOnkeydown     = iota
-	kAttrOnkeypress    = iota
-	kAttrOnkeyup       = iota
-	kAttrOnmousedown   = iota
-	kAttrOnmousemove   = iota
-	kAttrOnmouseout    = iota
-	kAttrOnmouseover   = iota
-	kAttrOnmouseup     = iota
-	kAttrOnselect      = iota
-	kAttrOnsubmit      = iota
-	kAttrPattern       = iota
-	kAttrPlaceholder   = iota
-	kAttrRowspan       = iota
-	kAttrReadonly      = iota
-	kAttrRel           = iota
-	kAttrRequired      = iota
-	kAttrRows          = iota
-	kAttrScope         = iota
-	kAttrSelected      = iota
-	kAttrSize          = iota
-	kAttrSrc           = iota
-	kAttrStep          = iota
-	kAttrTarget        = iota
-	kAttrTitle         = iota
-	kAttrType          = iota
-	kAttrUsemap        = iota
-	kAttrValue         = iota
-	kAttrWidth         = iota
-	kAttrWrap          = iota
+	kAttrAccept                   = iota
+	kAttrAcceptCharset            = iota
+	kAttrAccesskey                = iota
+	kAttrAction                   = iota
+	kAttrAllow                    = iota
+	kAttrAllowfullscreen          = iota
+	kAttrAlt                      = iota
+	kAttrAs                       = iota
+	kAttrAsync                    = iota
+	kAttrAutocomplete             = iota
+	kAttrAutofocus                = iota
+	kAttrAutoplay                 = iota
+	kAttrBorder                   = iota
+	kAttrCapture                  = iota
+	kAttrCharset                  = iota
+	kAttrChecked                  = iota
+	kAttrCite                     = iota
+	kAttrClass                    = iota
+	kAttrCols                     = iota
+	kAttrColspan                  = iota
+	kAttrContent                  = iota
+	kAttrContenteditable          = iota
+	kAttrControls                 = iota
+	kAttrCrossorigin              = iota
+	kAttrData                     = iota
+	kAttrDatetime                 = iota
+	kAttrDecoding                 = iota
+	kAttrDefault                  = iota
+	kAttrDefer                    = iota
+	kAttrDir                      = iota
+	kAttrDirname                  = iota
+	kAttrDisabled                 = iota
+	kAttrDownload                 = iota
+	kAttrDraggable                = iota
+	kAttrEnctype                  = iota
+	kAttrEnterkeyhint             = iota
+	kAttrFetchpriority            = iota
+	kAttrFor                      = iota
+	kAttrForm                     = iota
+	kAttrFormaction               = iota
+	kAttrFormenctype              = iota
+	kAttrFormmethod               = iota
+	kAttrFormnovalidate           = iota
+	kAttrFormtarget               = iota
+	kAttrHeaders                  = iota
+	kAttrHeight                   = iota
+	kAttrHidden                   = iota
+	kAttrHigh                     = iota
+	kAttrHref                     = iota
+	kAttrHreflang                 = iota
+	kAttrHttpEquiv                = iota
+	kAttrId                       = iota
+	kAttrImagesizes               = iota
+	kAttrImagesrcset              = iota
+	kAttrInert                    = iota
+	kAttrInputmode                = iota
+	kAttrIntegrity                = iota
+	kAttrIsmap                    = iota
+	kAttrKind                     = iota
+	kAttrLabel                    = iota
+	kAttrLang                     = iota
+	kAttrList                     = iota
+	kAttrLoading                  = iota
+	kAttrLoop                     = iota
+	kAttrLow                      = iota
+	kAttrManifest                 = iota
+	kAttrMax                      = iota
+	kAttrMaxlength                = iota
+	kAttrMedia                    = iota
+	kAttrMethod                   = iota
+	kAttrMin                      = iota
+	kAttrMinlength                = iota
+	kAttrMultiple                 = iota
+	kAttrMuted                    = iota
+	kAttrName                     = iota
+	kAttrNomodule                 = iota
+	kAttrNonce                    = iota
+	kAttrOpen                     = iota
+	kAttrOptimum                  = iota
+	kAttrPattern                  = iota
+	kAttrPing                     = iota
+	kAttrPlaceholder              = iota
+	kAttrPlaysinline              = iota
+	kAttrPoster                   = iota
+	kAttrPreload                  = iota
+	kAttrReferrerpolicy           = iota
+	kAttrRowspan                  = iota
+	kAttrReadonly                 = iota
+	kAttrRel                      = iota
+	kAttrRequired                 = iota
+	kAttrRole                     = iota
+	kAttrRows                     = iota
+	kAttrSandbox                  = iota
+	kAttrScope                    = iota
+	kAttrSelected                 = iota
+	kAttrShadowrootclonable       = iota
+	kAttrShadowrootdelegatesfocus = iota
+	kAttrShadowrootmode           = iota
+	kAttrShadowrootserializable   = iota
+	kAttrSize                     = iota
+	kAttrSizes                    = iota
+	kAttrSpellcheck               = iota
+	kAttrSrc                      = iota
+	kAttrSrcdoc                   = iota
+	kAttrSrclang                  = iota
+	kAttrSrcset                   = iota
+	kAttrStep                     = iota
+	kAttrStyle                    = iota
+	kAttrTabindex                 = iota
+	kAttrTarget                   = iota
+	kAttrTitle                    = iota
+	kAttrTranslate                = iota
+	kAttrType                     = iota
+	kAttrUsemap                   = iota
+	kAttrValue                    = iota
+	kAttrWidth                    = iota
+	kAttrWrap                     = iota
 
 	// The number of attributes.
 	kAttrMAXCOUNT = iota
 )
 
+// Prefixes for attribute families.
+const (
+	kAttrPrefixAria = "aria-"
+	kAttrPrefixData = "data-"
+	// Event handler attributes.
+	kAttrPrefixEvent = "on"
+)
+
 // HTML constants
 const (
+	kHtmlFalse = "false"
+	kHtmlTrue  = "true"
+
 	kHtmlOff = "off"
 	kHtmlOn  = "on"
 
@@ -108,7 +169,12 @@ const (
 	kTagTypeA          = iota
 	kTagTypeAbbr       = iota
 	kTagTypeAddress    = iota
+	kTagTypeArticle    = iota
+	kTagTypeAside      = iota
+	kTagTypeAudio      = iota
 	kTagTypeB          = iota
+	kTagTypeBdi        = iota
+	kTagTypeBdo        = iota
 	kTagTypeBlockquote = iota
 	kTagTypeBody       = iota
 	kTagTypeBr         = iota
@@ -117,13 +183,21 @@ const (
 	kTagTypeCaption    = iota
 	kTagTypeCite       = iota
 	kTagTypeCode       = iota
+	kTagTypeData       = iota
 	kTagTypeDatalist   = iota
+	kTagTypeDel        = iota
+	kTagTypeDetails    = iota
 	kTagTypeDfn        = iota
+	kTagTypeDialog     = iota
 	kTagTypeDiv        = iota
 	kTagTypeDd         = iota
 	kTagTypeDl         = iota
 	kTagTypeDt         = iota
 	kTagTypeEm         = iota
+	kTagTypeEmbed      = iota
+	kTagTypeFieldset   = iota
+	kTagTypeFigcaption = iota
+	kTagTypeFigure     = iota
 	kTagTypeFooter     = iota
 	kTagTypeForm       = iota
 	kTagTypeH1         = iota
@@ -133,46 +207,83 @@ const (
 	kTagTypeH5         = iota
 	kTagTypeH6         = iota
 	kTagTypeHead       = iota
+	kTagTypeHeader     = iota
+	kTagTypeHgroup     = iota
 	kTagTypeHr         = iota
 	kTagTypeHtml       = iota
 	kTagTypeI          = iota
+	kTagTypeIframe     = iota
 	kTagTypeImg        = iota
 	kTagTypeInput      = iota
+	kTagTypeIns        = iota
 	kTagTypeKbd        = iota
 	kTagTypeLabel      = iota
+	kTagTypeLegend     = iota
 	kTagTypeLi         = iota
 	kTagTypeLink       = iota
+	kTagTypeMain       = iota
+	kTagTypeMark       = iota
+	kTagTypeMenu       = iota
 	kTagTypeMeta       = iota
+	kTagTypeMeter      = iota
+	kTagTypeNav        = iota
 	kTagTypeNoScript   = iota
+	kTagTypeObject     = iota
 	kTagTypeOl         = iota
+	kTagTypeOptgroup   = iota
 	kTagTypeOption     = iota
+	kTagTypeOutput     = iota
 	kTagTypeP          = iota
+	kTagTypeParam      = iota
+	kTagTypePicture    = iota
 	kTagTypePre        = iota
+	kTagTypeProgress   = iota
+	kTagTypeQ          = iota
+	kTagTypeRp         = iota
+	kTagTypeRt         = iota
+	kTagTypeRuby       = iota
+	kTagTypeS          = iota
 	kTagTypeSamp       = iota
 	kTagTypeScript     = iota
+	kTagTypeSearch     = iota
+	kTagTypeSection    = iota
 	kTagTypeSelect     = iota
+	kTagTypeSlot       = iota
 	kTagTypeSmall      = iota
+	kTagTypeSource     = iota
 	kTagTypeSpan       = iota
 	kTagTypeStrong     = iota
 	kTagTypeStyle      = iota
+	kTagTypeSub        = iota
+	kTagTypeSummary    = iota
+	kTagTypeSup        = iota
 	kTagTypeTable      = iota
 	kTagTypeTbody      = iota
 	kTagTypeTd         = iota
+	kTagTypeTemplate   = iota
 	kTagTypeTextarea   = iota
 	kTagTypeTfoot      = iota
 	kTagTypeTh         = iota
 	kTagTypeThead      = iota
+	kTagTypeTime       = iota
 	kTagTypeTitle      = iota
 	kTagTypeTr         = iota
+	kTagTypeTrack      = iota
 	kTagTypeU          = iota
 	kTagTypeUl         = iota
 	kTagTypeVar        = iota
+	kTagTypeVideo      = iota
+	kTagTypeWbr        = iota
 
 	// The number of tag types.
 	kTagTypeMAXCOUNT = iota
 
 	// The Null tag prints nothing.
 	kTagTypeNull = iota
+
+	// Tags whose element name is given by baseTag.name, such as custom
+	// elements.
+	kTagTypeCustom = iota
 )
 
 type CheckedInputType string
@@ -182,21 +293,351 @@ const (
 	CheckedInputTypeRadio    = CheckedInputType(InputTypeRadio)
 )
 
+// Autofill tokens for the autocomplete attribute of <input>.
+type AutocompleteToken string
+
+const (
+	AutocompleteOff = AutocompleteToken("off")
+	AutocompleteOn  = AutocompleteToken("on")
+
+	// Section hints, which precede a field token.
+	AutocompleteBilling  = AutocompleteToken("billing")
+	AutocompleteShipping = AutocompleteToken("shipping")
+
+	AutocompleteAddressLevel1   = AutocompleteToken("address-level1")
+	AutocompleteAddressLevel2   = AutocompleteToken("address-level2")
+	AutocompleteBday            = AutocompleteToken("bday")
+	AutocompleteCcExp           = AutocompleteToken("cc-exp")
+	AutocompleteCcName          = AutocompleteToken("cc-name")
+	AutocompleteCcNumber        = AutocompleteToken("cc-number")
+	AutocompleteCountry         = AutocompleteToken("country")
+	AutocompleteCurrentPassword = AutocompleteToken("current-password")
+	AutocompleteEmail           = AutocompleteToken("email")
+	AutocompleteFamilyName      = AutocompleteToken("family-name")
+	AutocompleteGivenName       = AutocompleteToken("given-name")
+	AutocompleteName            = AutocompleteToken("name")
+	AutocompleteNewPassword     = AutocompleteToken("new-password")
+	AutocompleteOneTimeCode     = AutocompleteToken("one-time-code")
+	AutocompleteOrganization    = AutocompleteToken("organization")
+	AutocompletePostalCode      = AutocompleteToken("postal-code")
+	AutocompleteStreetAddress   = AutocompleteToken("street-address")
+	AutocompleteTel             = AutocompleteToken("tel")
+	AutocompleteUrl             = AutocompleteToken("url")
+	AutocompleteUsername        = AutocompleteToken("username")
+)
+
+// Values for the capture attribute of file inputs.
+type Capture string
+
+const (
+	CaptureEnvironment = Capture("environment")
+	CaptureUser        = Capture("user")
+)
+
+// Values for the contenteditable attribute.
+type ContentEditable string
+
+const (
+	ContentEditableFalse         = ContentEditable("false")
+	ContentEditablePlaintextOnly = ContentEditable("plaintext-only")
+	ContentEditableTrue          = ContentEditable("true")
+)
+
+// Values for the crossorigin attribute.
+type CrossOrigin string
+
+const (
+	CrossOriginAnonymous      = CrossOrigin("anonymous")
+	CrossOriginUseCredentials = CrossOrigin("use-credentials")
+)
+
+// Values for the decoding attribute of <img>.
+type Decoding string
+
+const (
+	DecodingAsync = Decoding("async")
+	DecodingAuto  = Decoding("auto")
+	DecodingSync  = Decoding("sync")
+)
+
+// Values for the dir attribute.
+type Dir string
+
+const (
+	DirAuto = Dir("auto")
+	DirLtr  = Dir("ltr")
+	DirRtl  = Dir("rtl")
+)
+
+// Values for the draggable attribute.
+type Draggable string
+
+const (
+	DraggableFalse = Draggable("false")
+	DraggableTrue  = Draggable("true")
+)
+
+// Values for the enterkeyhint attribute.
+type EnterKeyHint string
+
+const (
+	EnterKeyHintDone     = EnterKeyHint("done")
+	EnterKeyHintEnter    = EnterKeyHint("enter")
+	EnterKeyHintGo       = EnterKeyHint("go")
+	EnterKeyHintNext     = EnterKeyHint("next")
+	EnterKeyHintPrevious = EnterKeyHint("previous")
+	EnterKeyHintSearch   = EnterKeyHint("search")
+	EnterKeyHintSend     = EnterKeyHint("send")
+)
+
+// Event names for SetOn().  The handler attribute is "on" + the event name.
+type EventName string
+
+const (
+	// Form events
+	EventBlur     = EventName("blur")
+	EventChange   = EventName("change")
+	EventFocus    = EventName("focus")
+	EventFocusin  = EventName("focusin")
+	EventFocusout = EventName("focusout")
+	EventInput    = EventName("input")
+	EventInvalid  = EventName("invalid")
+	EventReset    = EventName("reset")
+	EventSearch   = EventName("search")
+	EventSelect   = EventName("select")
+	EventSubmit   = EventName("submit")
+
+	// Keyboard events
+	EventKeydown  = EventName("keydown")
+	EventKeypress = EventName("keypress")
+	EventKeyup    = EventName("keyup")
+
+	// Mouse events
+	EventClick       = EventName("click")
+	EventContextmenu = EventName("contextmenu")
+	EventDblclick    = EventName("dblclick")
+	EventMousedown   = EventName("mousedown")
+	EventMouseenter  = EventName("mouseenter")
+	EventMouseleave  = EventName("mouseleave")
+	EventMousemove   = EventName("mousemove")
+	EventMouseout    = EventName("mouseout")
+	EventMouseover   = EventName("mouseover")
+	EventMouseup     = EventName("mouseup")
+	EventWheel       = EventName("wheel")
+
+	// Pointer events
+	EventPointercancel = EventName("pointercancel")
+	EventPointerdown   = EventName("pointerdown")
+	EventPointerenter  = EventName("pointerenter")
+	EventPointerleave  = EventName("pointerleave")
+	EventPointermove   = EventName("pointermove")
+	EventPointerout    = EventName("pointerout")
+	EventPointerover   = EventName("pointerover")
+	EventPointerup     = EventName("pointerup")
+
+	// Touch events
+	EventTouchcancel = EventName("touchcancel")
+	EventTouchend    = EventName("touchend")
+	EventTouchmove   = EventName("touchmove")
+	EventTouchstart  = EventName("touchstart")
+
+	// Drag and drop events
+	EventDrag      = EventName("drag")
+	EventDragend   = EventName("dragend")
+	EventDragenter = EventName("dragenter")
+	EventDragleave = EventName("dragleave")
+	EventDragover  = EventName("dragover")
+	EventDragstart = EventName("dragstart")
+	EventDrop      = EventName("drop")
+
+	// Clipboard events
+	EventCopy  = EventName("copy")
+	EventCut   = EventName("cut")
+	EventPaste = EventName("paste")
+
+	// Resource events, such as for <body>, <img> and <script>.
+	EventAbort  = EventName("abort")
+	EventError  = EventName("error")
+	EventLoad   = EventName("load")
+	EventUnload = EventName("unload")
+
+	// Window events, which are set on <body>.
+	EventBeforeunload = EventName("beforeunload")
+	EventHashchange   = EventName("hashchange")
+	EventMessage      = EventName("message")
+	EventPopstate     = EventName("popstate")
+	EventResize       = EventName("resize")
+
+	// Media events
+	EventCanplay      = EventName("canplay")
+	EventEnded        = EventName("ended")
+	EventPause        = EventName("pause")
+	EventPlay         = EventName("play")
+	EventTimeupdate   = EventName("timeupdate")
+	EventVolumechange = EventName("volumechange")
+
+	// Other events
+	EventAnimationend  = EventName("animationend")
+	EventCancel        = EventName("cancel")
+	EventClose         = EventName("close")
+	EventScroll        = EventName("scroll")
+	EventToggle        = EventName("toggle")
+	EventTransitionend = EventName("transitionend")
+)
+
+// Values for the fetchpriority attribute.
+type FetchPriority string
+
+const (
+	FetchPriorityAuto = FetchPriority("auto")
+	FetchPriorityHigh = FetchPriority("high")
+	FetchPriorityLow  = FetchPriority("low")
+)
+
+// Values for the inputmode attribute.
+type InputMode string
+
+const (
+	InputModeDecimal = InputMode("decimal")
+	InputModeEmail   = InputMode("email")
+	InputModeNone    = InputMode("none")
+	InputModeNumeric = InputMode("numeric")
+	InputModeSearch  = InputMode("search")
+	InputModeTel     = InputMode("tel")
+	InputModeText    = InputMode("text")
+	InputModeUrl     = InputMode("url")
+)
+
 type InputType string
 
 const (
-	InputTypeButton   = InputType("button")
-	InputTypeCheckbox = InputType("checkbox")
-	InputTypeFile     = InputType("file")
-	InputTypeHidden   = InputType("hidden")
-	InputTypeImage    = InputType("image")
-	InputTypeNumber   = InputType("number")
-	InputTypePassword = InputType("password")
-	InputTypeRadio    = InputType("radio")
-	InputTypeRange    = InputType("range")
-	InputTypeReset    = InputType("reset")
-	InputTypeSubmit   = InputType("submit")
-	InputTypeText     = InputType("text")
+	InputTypeButton        = InputType("button")
+	InputTypeCheckbox      = InputType("checkbox")
+	InputTypeColor         = InputType("color")
+	InputTypeDate          = InputType("date")
+	InputTypeDateTimeLocal = InputType("datetime-local")
+	InputTypeEmail         = InputType("email")
+	InputTypeFile          = InputType("file")
+	InputTypeHidden        = InputType("hidden")
+	InputTypeImage         = InputType("image")
+	InputTypeMonth         = InputType("month")
+	InputTypeNumber        = InputType("number")
+	InputTypePassword      = InputType("password")
+	InputTypeRadio         = InputType("radio")
+	InputTypeRange         = InputType("range")
+	InputTypeReset         = InputType("reset")
+	InputTypeSearch        = InputType("search")
+	InputTypeSubmit        = InputType("submit")
+	InputTypeTel           = InputType("tel")
+	InputTypeText          = InputType("text")
+	InputTypeTime          = InputType("time")
+	InputTypeUrl           = InputType("url")
+	InputTypeWeek          = InputType("week")
+)
+
+// Values for the loading attribute of <img> and <iframe>.
+type Loading string
+
+const (
+	LoadingEager = Loading("eager")
+	LoadingLazy  = Loading("lazy")
+)
+
+// Destinations for the as attribute of preload links.
+type LinkAs string
+
+const (
+	LinkAsAudio    = LinkAs("audio")
+	LinkAsDocument = LinkAs("document")
+	LinkAsEmbed    = LinkAs("embed")
+	LinkAsFetch    = LinkAs("fetch")
+	LinkAsFont     = LinkAs("font")
+	LinkAsImage    = LinkAs("image")
+	LinkAsObject   = LinkAs("object")
+	LinkAsScript   = LinkAs("script")
+	LinkAsStyle    = LinkAs("style")
+	LinkAsTrack    = LinkAs("track")
+	LinkAsVideo    = LinkAs("video")
+	LinkAsWorker   = LinkAs("worker")
+)
+
+// Modes for declarative shadow roots.
+type ShadowRootMode string
+
+const (
+	ShadowRootModeClosed = ShadowRootMode("closed")
+	ShadowRootModeOpen   = ShadowRootMode("open")
+)
+
+// Values for the preload attribute of <audio> and <video>.
+type Preload string
+
+const (
+	PreloadAuto     = Preload("auto")
+	PreloadMetadata = Preload("metadata")
+	PreloadNone     = Preload("none")
+)
+
+// Values for the referrerpolicy attribute.
+type ReferrerPolicy string
+
+const (
+	ReferrerPolicyNoReferrer                  = ReferrerPolicy("no-referrer")
+	ReferrerPolicyNoReferrerWhenDowngrade     = ReferrerPolicy("no-referrer-when-downgrade")
+	ReferrerPolicyOrigin                      = ReferrerPolicy("origin")
+	ReferrerPolicyOriginWhenCrossOrigin       = ReferrerPolicy("origin-when-cross-origin")
+	ReferrerPolicySameOrigin                  = ReferrerPolicy("same-origin")
+	ReferrerPolicyStrictOrigin                = ReferrerPolicy("strict-origin")
+	ReferrerPolicyStrictOriginWhenCrossOrigin = ReferrerPolicy("strict-origin-when-cross-origin")
+	ReferrerPolicyUnsafeUrl                   = ReferrerPolicy("unsafe-url")
+)
+
+// Tokens for the sandbox attribute of <iframe>, each of which lifts a
+// restriction.
+type SandboxToken string
+
+const (
+	SandboxAllowDownloads                      = SandboxToken("allow-downloads")
+	SandboxAllowForms                          = SandboxToken("allow-forms")
+	SandboxAllowModals                         = SandboxToken("allow-modals")
+	SandboxAllowOrientationLock                = SandboxToken("allow-orientation-lock")
+	SandboxAllowPointerLock                    = SandboxToken("allow-pointer-lock")
+	SandboxAllowPopups                         = SandboxToken("allow-popups")
+	SandboxAllowPopupsToEscapeSandbox          = SandboxToken("allow-popups-to-escape-sandbox")
+	SandboxAllowPresentation                   = SandboxToken("allow-presentation")
+	SandboxAllowSameOrigin                     = SandboxToken("allow-same-origin")
+	SandboxAllowScripts                        = SandboxToken("allow-scripts")
+	SandboxAllowStorageAccessByUserActivation  = SandboxToken("allow-storage-access-by-user-activation")
+	SandboxAllowTopNavigation                  = SandboxToken("allow-top-navigation")
+	SandboxAllowTopNavigationByUserActivation  = SandboxToken("allow-top-navigation-by-user-activation")
+	SandboxAllowTopNavigationToCustomProtocols = SandboxToken("allow-top-navigation-to-custom-protocols")
+)
+
+// Values for the spellcheck attribute.
+type Spellcheck string
+
+const (
+	SpellcheckFalse = Spellcheck("false")
+	SpellcheckTrue  = Spellcheck("true")
+)
+
+// Values for the kind attribute of <track>.
+type TrackKind string
+
+const (
+	TrackKindCaptions     = TrackKind("captions")
+	TrackKindChapters     = TrackKind("chapters")
+	TrackKindDescriptions = TrackKind("descriptions")
+	TrackKindMetadata     = TrackKind("metadata")
+	TrackKindSubtitles    = TrackKind("subtitles")
+)
+
+// Values for the translate attribute.
+type Translate string
+
+const (
+	TranslateNo  = Translate("no")
+	TranslateYes = Translate("yes")
 )
 
 // Common HTML constants.
@@ -219,19 +660,42 @@ const (
 
 	HeaderContentType = "Content-Type"
 
-	LinkRelAlternate    = "alternate"
-	LinkRelAuthor       = "author"
-	LinkRelHelp         = "help"
-	LinkRelIcon         = "icon"
-	LinkRelLicense      = "license"
-	LinkRelNext         = "next"
-	LinkRelNofollow     = "nofollow"
-	LinkRelNoreferrer   = "noreferrer"
-	LinkRelPrefetch     = "prefetch"
-	LinkRelPrev         = "prev"
-	LinkRelSearch       = "search"
-	LinkRelShortcutIcon = "shortcut icon"
-	LinkRelStylesheet   = "stylesheet"
+	// DOCTYPE declarations for RootOptions.
+	DoctypeHtml               = "<!DOCTYPE html>"
+	DoctypeHtml4Strict        = `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01//EN" "http://www.w3.org/TR/html4/strict.dtd">`
+	DoctypeXhtml1Strict       = `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd">`
+	DoctypeXhtml1Transitional = `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">`
+
+	// Browsing context names for the target attribute of <a>.
+	TargetBlank  = "_blank"
+	TargetParent = "_parent"
+	TargetSelf   = "_self"
+	TargetTop    = "_top"
+
+	LinkRelAlternate      = "alternate"
+	LinkRelAppleTouchIcon = "apple-touch-icon"
+	LinkRelAuthor         = "author"
+	LinkRelBookmark       = "bookmark"
+	LinkRelCanonical      = "canonical"
+	LinkRelDnsPrefetch    = "dns-prefetch"
+	LinkRelExternal       = "external"
+	LinkRelHelp           = "help"
+	LinkRelIcon           = "icon"
+	LinkRelLicense        = "license"
+	LinkRelManifest       = "manifest"
+	LinkRelModulepreload  = "modulepreload"
+	LinkRelNext           = "next"
+	LinkRelNofollow       = "nofollow"
+	LinkRelNoopener       = "noopener"
+	LinkRelNoreferrer     = "noreferrer"
+	LinkRelOpener         = "opener"
+	LinkRelPreconnect     = "preconnect"
+	LinkRelPrefetch       = "prefetch"
+	LinkRelPreload        = "preload"
+	LinkRelPrev           = "prev"
+	LinkRelSearch         = "search"
+	LinkRelShortcutIcon   = "shortcut icon"
+	LinkRelStylesheet     = "stylesheet"
 
 	MetaNameViewport = "viewport"
 
@@ -247,6 +711,10 @@ const (
 	MimeTypeHtml       = "text/html"
 	MimeTypeJavascript = "text/javascript"
 
+	// Script types that change how a <script> is interpreted.
+	ScriptTypeImportmap = "importmap"
+	ScriptTypeModule    = "module"
+
 	MimeTypeGif = "image/gif"
 	MimeTypePng = "image/png"
 )
@@ -260,68 +728,118 @@ var tagTypeStringMap [kTagTypeMAXCOUNT]string
 func init() {
 	// This must match the order of the kAttrs.
 	attrStringMap = [kAttrMAXCOUNT]string{
+		"accept",
 		"accept-charset",
+		"accesskey",
 		"action",
+		"allow",
+		"allowfullscreen",
 		"alt",
+		"as",
+		"async",
 		"autocomplete",
 		"autofocus",
+		"autoplay",
 		"border",
+		"capture",
 		"charset",
 		"checked",
+		"cite",
 		"class",
 		"cols",
 		"colspan",
 		"content",
+		"contenteditable",
+		"controls",
+		"crossorigin",
+		"data",
+		"datetime",
+		"decoding",
+		"default",
+		"defer",
+		"dir",
+		"dirname",
 		"disabled",
+		"download",
+		"draggable",
 		"enctype",
+		"enterkeyhint",
+		"fetchpriority",
 		"for",
 		"form",
+		"formaction",
+		"formenctype",
+		"formmethod",
+		"formnovalidate",
+		"formtarget",
 		"headers",
 		"height",
+		"hidden",
+		"high",
 		"href",
 		"hreflang",
 		"http-equiv",
 		"id",
+		"imagesizes",
+		"imagesrcset",
+		"inert",
+		"inputmode",
+		"integrity",
 		"ismap",
+		"kind",
 		"label",
+		"lang",
 		"list",
+		"loading",
+		"loop",
+		"low",
+		"manifest",
 		"max",
 		"maxlength",
 		"media",
 		"method",
 		"min",
+		"minlength",
 		"multiple",
+		"muted",
 		"name",
-		"onblur",
-		"onchange",
-		"onclick",
-		"ondblclick",
-		"onfocus",
-		"onload",
-		"onkeydown",
-		"onkeypress",
-		"onkeyup",
-		"onmousedown",
-		"onmousemove",
-		"onmouseout",
-		"onmouseover",
-		"onmouseup",
-		"onselect",
-		"onsubmit",
+		"nomodule",
+		"nonce",
+		"open",
+		"optimum",
 		"pattern",
+		"ping",
 		"placeholder",
+		"playsinline",
+		"poster",
+		"preload",
+		"referrerpolicy",
 		"rowspan",
 		"readonly",
 		"rel",
 		"required",
+		"role",
 		"rows",
+		"sandbox",
 		"scope",
 		"selected",
+		"shadowrootclonable",
+		"shadowrootdelegatesfocus",
+		"shadowrootmode",
+		"shadowrootserializable",
 		"size",
+		"sizes",
+		"spellcheck",
 		"src",
+		"srcdoc",
+		"srclang",
+		"srcset",
 		"step",
+		"style",
+		"tabindex",
 		"target",
 		"title",
+		"translate",
 		"type",
 		"usemap",
 		"value",
@@ -334,7 +852,12 @@ func init() {
 		"a",
 		"abbr",
 		"address",
+		"article",
+		"aside",
+		"audio",
 		"b",
+		"bdi",
+		"bdo",
 		"blockquote",
 		"body",
 		"br",
@@ -343,13 +866,21 @@ func init() {
 		"caption",
 		"cite",
 		"code",
+		"data",
 		"datalist",
+		"del",
+		"details",
 		"dfn",
+		"dialog",
 		"div",
 		"dd",
 		"dl",
 		"dt",
 		"em",
+		"embed",
+		"fieldset",
+		"figcaption",
+		"figure",
 		"footer",
 		"form",
 		"h1",
@@ -359,39 +890,72 @@ func init() {
 		"h5",
 		"h6",
 		"head",
+		"header",
+		"hgroup",
 		"hr",
 		"html",
 		"i",
+		"iframe",
 		"img",
 		"input",
+		"ins",
 		"kbd",
 		"label",
+		"legend",
 		"li",
 		"link",
+		"main",
+		"mark",
+		"menu",
 		"meta",
+		"meter",
+		"nav",
 		"noscript",
+		"object",
 		"ol",
+		"optgroup",
 		"option",
+		"output",
 		"p",
+		"param",
+		"picture",
 		"pre",
+		"progress",
+		"q",
+		"rp",
+		"rt",
+		"ruby",
+		"s",
 		"samp",
 		"script",
+		"search",
+		"section",
 		"select",
+		"slot",
 		"small",
+		"source",
 		"span",
 		"strong",
 		"style",
+		"sub",
+		"summary",
+		"sup",
 		"table",
 		"tbody",
 		"td",
+		"template",
 		"textarea",
 		"tfoot",
 		"th",
 		"thead",
+		"time",
 		"title",
 		"tr",
+		"track",
 		"u",
 		"ul",
 		"var",
+		"video",
+		"wbr",
 	}
 }
@@ -0,0 +1,79 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"io"
+)
+
+// An element from a foreign namespace, such as SVG or MathML.  Element and
+// attribute names are case-sensitive, so attributes are stored verbatim as
+// custom attributes.  Elements without children are self-closing.
+type foreignTag struct {
+	baseTag
+}
+
+func newForeignTag(name string) foreignTag {
+	newTag := newBaseTag(kTagTypeCustom)
+	newTag.name = name
+	return foreignTag{*newTag}
+}
+
+// Returns true if t has no visible children and should self-close.
+func (t *foreignTag) isEmpty() bool {
+	for _, child := range t.children {
+		if !child.isHidden() {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *foreignTag) write(writer io.Writer, ctx *renderContext) (n int, err error) {
+	if t.hidden {
+		return
+	}
+	if !t.isEmpty() {
+		return t.baseTag.write(writer, ctx)
+	}
+
+	if count, err := t.writeOpenTagLeadCached(writer, t.tagStr(), ctx); err != nil {
+		return n, err
+	} else {
+		n += count
+	}
+	if count, err := io.WriteString(writer, " />"); err != nil {
+		return n, err
+	} else {
+		n += count
+	}
+	return
+}
+
+func (t *foreignTag) writePretty(writer io.Writer, indent int, ctx *renderContext) (n int, err error) {
+	if t.hidden {
+		return
+	}
+	if !t.isEmpty() {
+		return t.baseTag.writePretty(writer, indent, ctx)
+	}
+
+	if count, err := writeIndent(writer, indent, ctx); err != nil {
+		return n, err
+	} else {
+		n += count
+	}
+	if count, err := t.writeOpenTagLeadSorted(writer, t.tagStr(), indent, ctx); err != nil {
+		return n, err
+	} else {
+		n += count
+	}
+	if count, err := io.WriteString(writer, " />"); err != nil {
+		return n, err
+	} else {
+		n += count
+	}
+	return
+}
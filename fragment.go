@@ -0,0 +1,231 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"errors"
+	"html"
+	"io"
+	"strings"
+)
+
+const (
+	kHxSwapOob = "hx-swap-oob"
+)
+
+var ErrInvalidSelector = errors.New("invalid selector")
+
+// Values for the hx-swap-oob attribute of out-of-band HTMX fragments.
+type HtmxSwap string
+
+const (
+	HtmxSwapAfterBegin  = HtmxSwap("afterbegin")
+	HtmxSwapAfterEnd    = HtmxSwap("afterend")
+	HtmxSwapBeforeBegin = HtmxSwap("beforebegin")
+	HtmxSwapBeforeEnd   = HtmxSwap("beforeend")
+	HtmxSwapDelete      = HtmxSwap("delete")
+	HtmxSwapInnerHtml   = HtmxSwap("innerHTML")
+	HtmxSwapNone        = HtmxSwap("none")
+	HtmxSwapOuterHtml   = HtmxSwap("outerHTML")
+	// Replaces the element with the same id.
+	HtmxSwapTrue = HtmxSwap("true")
+)
+
+// An out-of-band fragment for RenderHtmxFragments().
+type HtmxFragment struct {
+	// An id selector (e.g., "#cart") for the subtree to render.
+	Selector string
+	// HtmxSwapTrue is used if empty.
+	Swap HtmxSwap
+}
+
+// Actions for <turbo-stream> elements.
+type TurboAction string
+
+const (
+	TurboActionAfter   = TurboAction("after")
+	TurboActionAppend  = TurboAction("append")
+	TurboActionBefore  = TurboAction("before")
+	TurboActionPrepend = TurboAction("prepend")
+	TurboActionRemove  = TurboAction("remove")
+	TurboActionReplace = TurboAction("replace")
+	TurboActionUpdate  = TurboAction("update")
+)
+
+// A Turbo Stream for RenderTurboStreams().
+type TurboStream struct {
+	Action TurboAction
+	// An id selector (e.g., "#new-item") for the subtree whose rendering
+	// is the content of the stream.  For TurboActionUpdate, only the
+	// subtree's children are rendered.  Defaults to the selector for
+	// Target, and is ignored for TurboActionRemove.
+	Selector string
+	// The id of the element in the page to act on.
+	Target string
+}
+
+// Returns the tag in the tree rooted at root whose id is id, or
+// ErrNotFound.  root itself is included in the search.
+func FindById(root Tag, id string) (Tag, error) {
+	if root.Id() == id {
+		return root, nil
+	}
+	for _, child := range root.getChildren() {
+		childTag, ok := child.(Tag)
+		if !ok {
+			// Text does not have an id.
+			continue
+		}
+		if found, err := FindById(childTag, id); err == nil {
+			return found, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Returns the tag in root that is selected by selector, which must be an id
+// selector (e.g., "#cart").
+func findBySelector(root Tag, selector string) (Tag, error) {
+	id := strings.TrimPrefix(selector, "#")
+	if len(id) == 0 || len(id) == len(selector) || strings.ContainsAny(id, " \t\n\f\r") {
+		return nil, ErrInvalidSelector
+	}
+	return FindById(root, id)
+}
+
+// Renders the subtree of root that is selected by selector, which must be an
+// id selector (e.g., "#cart").  Returns ErrNotFound if there is no such
+// subtree.  options may be nil.
+func RenderFragment(writer io.Writer, root Tag, selector string, options *RenderOptions) (int, error) {
+	tag, err := findBySelector(root, selector)
+	if err != nil {
+		return 0, err
+	}
+	return tag.write(writer, newRenderContext(options))
+}
+
+// Renders each fragment of root with its hx-swap-oob attribute assigned, for
+// out-of-band swaps in a single HTMX response.  The attribute is assigned
+// only in the output, and root is not modified, so the same tree may be
+// used to render the full page.
+func RenderHtmxFragments(writer io.Writer, root Tag, fragments []HtmxFragment, options *RenderOptions) (n int, err error) {
+	ctx := newRenderContext(options)
+	for _, fragment := range fragments {
+		tag, err := findBySelector(root, fragment.Selector)
+		if err != nil {
+			return n, err
+		}
+
+		swap := fragment.Swap
+		if len(swap) == 0 {
+			swap = HtmxSwapTrue
+		}
+
+		ctx.overrideAttrs = map[string]string{kHxSwapOob: string(swap)}
+		count, err := tag.write(writer, ctx)
+		// The override is unused if tag is hidden.
+		ctx.overrideAttrs = nil
+		n += count
+		if err != nil {
+			return n, err
+		}
+	}
+	return
+}
+
+// Renders each stream as a <turbo-stream> element whose <template> holds the
+// rendering of the selected subtree of root.
+func RenderTurboStreams(writer io.Writer, root Tag, streams []TurboStream, options *RenderOptions) (n int, err error) {
+	ctx := newRenderContext(options)
+	for _, stream := range streams {
+		var content []tagWriter
+		if stream.Action != TurboActionRemove {
+			selector := stream.Selector
+			if len(selector) == 0 {
+				selector = "#" + stream.Target
+			}
+			tag, err := findBySelector(root, selector)
+			if err != nil {
+				return n, err
+			}
+
+			if stream.Action == TurboActionUpdate {
+				content = tag.getChildren()
+			} else {
+				content = []tagWriter{tag}
+			}
+		}
+
+		count, err := writeTurboStream(writer, stream, content, ctx)
+		n += count
+		if err != nil {
+			return n, err
+		}
+	}
+	return
+}
+
+// Like RenderFragment() but in RenderModeXhtml, as with Write().  env is
+// optional, and only the first one will be used.
+func WriteFragment(writer io.Writer, root Tag, selector string, env ...Environment) (int, error) {
+	return RenderFragment(writer, root, selector, envOptions(env))
+}
+
+// Writes a <turbo-stream> element holding content.  The <template> is
+// omitted for TurboActionRemove.
+func writeTurboStream(writer io.Writer, stream TurboStream, content []tagWriter, ctx *renderContext) (n int, err error) {
+	if count, err := io.WriteString(writer, "<turbo-stream "); err != nil {
+		return n, err
+	} else {
+		n += count
+	}
+	if count, err := writeKeyValue(writer, "action", html.EscapeString(string(stream.Action)), ctx.mode); err != nil {
+		return n, err
+	} else {
+		n += count
+	}
+	if count, err := writeRune(writer, ' '); err != nil {
+		return n, err
+	} else {
+		n += count
+	}
+	if count, err := writeKeyValue(writer, "target", html.EscapeString(stream.Target), ctx.mode); err != nil {
+		return n, err
+	} else {
+		n += count
+	}
+	if count, err := writeRune(writer, '>'); err != nil {
+		return n, err
+	} else {
+		n += count
+	}
+
+	if stream.Action != TurboActionRemove {
+		if count, err := io.WriteString(writer, "<template>"); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
+		for _, child := range content {
+			if count, err := child.write(writer, ctx); err != nil {
+				return n, err
+			} else {
+				n += count
+			}
+		}
+		if count, err := io.WriteString(writer, "</template>"); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
+	}
+
+	if count, err := io.WriteString(writer, "</turbo-stream>"); err != nil {
+		return n, err
+	} else {
+		n += count
+	}
+	return
+}
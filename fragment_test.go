@@ -0,0 +1,150 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// Calls check before each write to w.
+type checkWriter struct {
+	w     io.Writer
+	check func()
+}
+
+func (w *checkWriter) Write(p []byte) (int, error) {
+	w.check()
+	return w.w.Write(p)
+}
+
+func newFragmentTestRoot() Tag {
+	root := NewRoot()
+	body := root.Body()
+	body.DivId("header").T("Shop")
+	cart := body.DivId("cart")
+	cart.SetAttribute(kHxSwapOob, "outerHTML")
+	cart.SpanId("count").TV("$count")
+	list := body.Ul()
+	list.SetId("items")
+	list.Li().SetId("item-1")
+	return root
+}
+
+func TestWriteFragment(t *testing.T) {
+	root := newFragmentTestRoot()
+
+	buf := new(bytes.Buffer)
+	if _, err := WriteFragment(buf, root, "#count", Environment{"count": StringValue("3")}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `<span id="count">3</span>` {
+		t.Errorf("unexpected fragment: %s", buf.String())
+	}
+
+	buf.Reset()
+	if _, err := RenderFragment(buf, root, "#header", &RenderOptions{Mode: RenderModeHtml}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `<div id="header">Shop</div>` {
+		t.Errorf("unexpected fragment: %s", buf.String())
+	}
+
+	if _, err := WriteFragment(buf, root, "#missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	for _, selector := range []string{"", "#", "cart", ".cart", "#a b"} {
+		if _, err := WriteFragment(buf, root, selector); err != ErrInvalidSelector {
+			t.Errorf("%q: expected ErrInvalidSelector, got %v", selector, err)
+		}
+	}
+}
+
+func TestRenderHtmxFragments(t *testing.T) {
+	// Known attributes, such as id, are written before others.
+	const kCompare = `<span id="count" hx-swap-oob="true">3</span>` +
+		`<div id="cart" hx-swap-oob="innerHTML"><span id="count">3</span></div>`
+
+	root := newFragmentTestRoot()
+	cart, _ := FindById(root, "cart")
+	buf := new(bytes.Buffer)
+	// The tree must not be modified while rendering, since it may be
+	// shared by concurrent renders.
+	writer := &checkWriter{buf, func() {
+		if swap, _ := cart.getAttribute(kHxSwapOob); swap != "outerHTML" {
+			t.Errorf("hx-swap-oob modified during render: %q", swap)
+		}
+	}}
+	_, err := RenderHtmxFragments(writer, root, []HtmxFragment{
+		{Selector: "#count"},
+		{Selector: "#cart", Swap: HtmxSwapInnerHtml},
+	}, &RenderOptions{Env: Environment{"count": StringValue("3")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != kCompare {
+		t.Errorf("expected:\n%s\ngot:\n%s", kCompare, buf.String())
+	}
+
+	// The attributes are restored for rendering the full page.
+	buf.Reset()
+	if _, err := WriteFragment(buf, root, "#header"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `<div id="header">Shop</div>` {
+		t.Errorf("unexpected fragment: %s", buf.String())
+	}
+	if swap, _ := cart.getAttribute(kHxSwapOob); swap != "outerHTML" {
+		t.Errorf("expected outerHTML, got %q", swap)
+	}
+	count, _ := FindById(root, "count")
+	if _, ok := count.getAttribute(kHxSwapOob); ok {
+		t.Error("expected hx-swap-oob to be removed")
+	}
+
+	if _, err := RenderHtmxFragments(buf, root, []HtmxFragment{{Selector: "#nope"}}, nil); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRenderTurboStreams(t *testing.T) {
+	const kCompare = `<turbo-stream action="update" target="cart"><template><span id="count">3</span></template></turbo-stream>` +
+		`<turbo-stream action="append" target="items"><template><li id="item-1"></li></template></turbo-stream>` +
+		`<turbo-stream action="replace" target="header"><template><div id="header">Shop</div></template></turbo-stream>` +
+		`<turbo-stream action="remove" target="banner"></turbo-stream>`
+
+	root := newFragmentTestRoot()
+	buf := new(bytes.Buffer)
+	_, err := RenderTurboStreams(buf, root, []TurboStream{
+		{Action: TurboActionUpdate, Target: "cart"},
+		{Action: TurboActionAppend, Selector: "#item-1", Target: "items"},
+		{Action: TurboActionReplace, Target: "header"},
+		{Action: TurboActionRemove, Target: "banner"},
+	}, &RenderOptions{Env: Environment{"count": StringValue("3")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != kCompare {
+		t.Errorf("expected:\n%s\ngot:\n%s", kCompare, buf.String())
+	}
+}
+
+func TestRenderTurboStreamsEscaping(t *testing.T) {
+	const kCompare = `<turbo-stream action="remove" target="a&#34;&gt;&lt;script&gt;"></turbo-stream>` +
+		`<turbo-stream action=remove target=a&#34;&gt;&lt;script&gt;></turbo-stream>`
+
+	buf := new(bytes.Buffer)
+	streams := []TurboStream{{Action: TurboActionRemove, Target: `a"><script>`}}
+	if _, err := RenderTurboStreams(buf, newFragmentTestRoot(), streams, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RenderTurboStreams(buf, newFragmentTestRoot(), streams, &RenderOptions{Mode: RenderModeHtmlMinified}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != kCompare {
+		t.Errorf("expected:\n%s\ngot:\n%s", kCompare, buf.String())
+	}
+}
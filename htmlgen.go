@@ -6,14 +6,19 @@
 package htmlgen
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"html"
 	"io"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
-const kIndentSpace = 2
+// The indentation for each level of WritePretty().
+const kIndentDefault = "  "
 
 // Patterns for input fields.
 const (
@@ -22,7 +27,86 @@ const (
 )
 
 var (
-	ErrNotFound = errors.New("not found")
+	ErrInvalidDataKey     = errors.New("invalid data attribute key")
+	ErrInvalidElementName = errors.New("invalid element name")
+	ErrInvalidShadowRoot  = errors.New("invalid shadow root")
+	ErrNotFound           = errors.New("not found")
+
+	// Matches element names for Element().  Names with a hyphen are custom
+	// element names, which are restricted here to ASCII.
+	reElementName = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9._-]*)?$`)
+
+	// Hyphenated names that are reserved by SVG and MathML and thus cannot
+	// be custom elements.
+	reservedElementNames = map[string]bool{
+		"annotation-xml":   true,
+		"color-profile":    true,
+		"font-face":        true,
+		"font-face-format": true,
+		"font-face-name":   true,
+		"font-face-src":    true,
+		"font-face-uri":    true,
+		"missing-glyph":    true,
+	}
+
+	// Built-in elements to which a shadow root may be attached.
+	shadowHostNames = map[string]bool{
+		"article":    true,
+		"aside":      true,
+		"blockquote": true,
+		"body":       true,
+		"div":        true,
+		"footer":     true,
+		"h1":         true,
+		"h2":         true,
+		"h3":         true,
+		"h4":         true,
+		"h5":         true,
+		"h6":         true,
+		"header":     true,
+		"main":       true,
+		"nav":        true,
+		"p":          true,
+		"section":    true,
+		"span":       true,
+	}
+
+	// Elements that the HTML parser treats as void, including obsolete
+	// ones.  Custom elements are never void.
+	voidElementNames = map[string]bool{
+		"area":     true,
+		"base":     true,
+		"basefont": true,
+		"bgsound":  true,
+		"br":       true,
+		"col":      true,
+		"embed":    true,
+		"frame":    true,
+		"hr":       true,
+		"img":      true,
+		"input":    true,
+		"keygen":   true,
+		"link":     true,
+		"meta":     true,
+		"param":    true,
+		"source":   true,
+		"track":    true,
+		"wbr":      true,
+	}
+
+	// Matches data attribute names for SetData(), after the conversion
+	// from camelCase.
+	reDataAttr = regexp.MustCompile(`^data-[a-z0-9._-]+$`)
+
+	// Matches ARIA attribute names for SetAria(), without the prefix.
+	reAriaName = regexp.MustCompile(`^[a-z]+$`)
+
+	// Matches event names for SetOn().
+	reEventName = regexp.MustCompile(`^[a-z]+$`)
+
+	// Matches CSS property names, including custom properties, which are
+	// case-sensitive.
+	reCssProperty = regexp.MustCompile(`^(--[A-Za-z0-9_-]*|-?[a-z][a-z0-9_-]*)$`)
 
 	// Default tag factory for creating detached nodes.
 	Factory = New()
@@ -31,12 +115,69 @@ var (
 // Factory for creating detached Tag nodes.
 var H TagFactory
 
+// Optional audio attributes.
+type AudioOptions struct {
+	Autoplay bool
+	Controls bool
+	Loop     bool
+	Muted    bool
+	Preload  Preload
+	Src      string
+}
+
+// Optional anchor attributes.
+type AOptions struct {
+	// Download the resource rather than navigating to it.  This is implied
+	// if DownloadName is set.
+	Download bool
+	// The suggested filename for the download.
+	DownloadName string
+	Hreflang     string
+	// URLs to notify when the link is followed.
+	Ping           []string
+	Referrerpolicy ReferrerPolicy
+	// Link types, such as LinkRelNofollow.  Duplicates are removed, and
+	// LinkRelNoopener and LinkRelNoreferrer are added if Target is
+	// TargetBlank.
+	Rel    []string
+	Target string
+	// A hint for the MIME type of the linked resource.
+	Type string
+}
+
 type CanvasOptions struct {
 	Id     string
 	Height int
 	Width  int
 }
 
+// Maps class names to whether they should be present.  This is useful for
+// conditional classes:
+//
+//	tag.ToggleClasses(ClassMap{"active": isActive, "disabled": !enabled})
+type ClassMap map[string]bool
+
+// Attributes for <ins> and <del>.
+type EditOptions struct {
+	// A URL explaining the change.
+	Cite string
+	// The time of the change, which is omitted if zero.
+	Datetime time.Time
+}
+
+type EmbedOptions struct {
+	// Height in pixels (non-zero to set)
+	Height int
+	// Width in pixels (non-zero to set)
+	Width int
+}
+
+type FieldsetOptions struct {
+	Disabled bool
+	Form     string
+	Name     string
+}
+
 // Optional form attributes.  We only specify those that are supported by
 // the major browsers (IE, Firefox, Chrome, Safari).
 type FormOptions struct {
@@ -50,46 +191,101 @@ type FormOptions struct {
 	Target          string
 }
 
+type IframeOptions struct {
+	// The permissions policy for the embedded document.
+	Allow           AllowPolicy
+	Allowfullscreen bool
+	// Height in pixels (non-zero to set)
+	Height         int
+	Loading        Loading
+	Name           string
+	Referrerpolicy ReferrerPolicy
+	// Applies all sandbox restrictions.  This is implied if SandboxAllow
+	// is non-empty.
+	Sandbox bool
+	// Restrictions to lift from the sandbox.
+	SandboxAllow []SandboxToken
+	// Srcdoc is rendered and escaped into the srcdoc attribute each time
+	// the iframe is written, with the same environment and render mode.
+	Srcdoc Tag
+	Title  string
+	// Width in pixels (non-zero to set)
+	Width int
+}
+
 type ImgOptions struct {
+	Crossorigin   CrossOrigin
+	Decoding      Decoding
+	Fetchpriority FetchPriority
 	// Height in pixels (non-zero to set)
-	Height int
-	Ismap  bool
+	Height         int
+	Ismap          bool
+	Loading        Loading
+	Referrerpolicy ReferrerPolicy
+	// The sizes attribute, which is required if Srcset uses width
+	// descriptors (e.g., "(max-width: 600px) 480px, 800px").
+	Sizes string
+	// Srcset is omitted if it is invalid; use Srcset.Validate() to check.
+	Srcset Srcset
 	Usemap string
 	// Width in pixels (non-zero to set)
 	Width int
 }
 
-// Input tag parameters
+// An import map for resolving module specifiers.  Each map is from a
+// specifier (or prefix ending in "/") to a URL.
+type ImportMap struct {
+	Imports map[string]string `json:"imports,omitempty"`
+	// Integrity metadata, keyed by URL.
+	Integrity map[string]string `json:"integrity,omitempty"`
+	// Imports that apply only to scripts under a URL prefix.
+	Scopes map[string]map[string]string `json:"scopes,omitempty"`
+}
+
+// Input tag parameters.  Pointer and interface fields are omitted if nil,
+// so that zero values may be assigned explicitly; use Int() for the integer
+// fields.
 type InputOptions struct {
+	// Accepted file types for file inputs (e.g., "image/*,.pdf").
+	Accept string
 	Action string
 	Alt    string
-	// Autocomplete is on by default.
-	AutocompleteOff bool
-	Checked         bool
-	Disabled        bool
-	// Only applied if > 0.
-	Height int
-	List   string
-	// Only applied if != 0.
-	Max float64
-	// Only applied if > 0.
-	Maxlength int
-	// Only applied if != 0.
-	Min         float64
+	// Autofill tokens, which are joined with spaces (e.g., shipping
+	// street-address).  Autocomplete is on by default.
+	Autocomplete []AutocompleteToken
+	// The camera to use for file inputs.
+	Capture  Capture
+	Checked  bool
+	Dirname  string
+	Disabled bool
+	// The label of the enter key on virtual keyboards.
+	Enterkeyhint EnterKeyHint
+	// Form overrides for submit and image inputs.
+	Formaction     string
+	Formenctype    string
+	Formmethod     string
+	Formnovalidate bool
+	Formtarget     string
+	Height         *int
+	// The kind of virtual keyboard to show.
+	Inputmode   InputMode
+	List        string
+	Max         InputValue
+	Maxlength   *int
+	Min         InputValue
+	Minlength   *int
+	Multiple    bool
 	Name        string
 	Pattern     string
 	Placeholder string
 	Readonly    bool
 	Required    bool
-	// Only applied if > 0.
-	Size int
-	// Only applied if != 0.
-	Step  float64
-	Src   string
-	Type  string
-	Value string
-	// Only applied if > 0.
-	Width int
+	Size        *int
+	Step        InputValue
+	Src         string
+	Type        string
+	Value       string
+	Width       *int
 }
 
 type LabelOptions struct {
@@ -99,10 +295,34 @@ type LabelOptions struct {
 
 // Link tag attributes
 type LinkOptions struct {
-	Href     string
-	Hreflang string
-	Media    string
-	Type     string
+	// The destination for preload links.
+	As          LinkAs
+	Crossorigin CrossOrigin
+	Href        string
+	Hreflang    string
+	// Image preload attributes, which mirror those of ImgOptions.
+	Imagesizes string
+	// Imagesrcset is omitted if it is invalid; use Srcset.Validate() to
+	// check.
+	Imagesrcset Srcset
+	// Subresource integrity metadata (e.g., "sha384-...").
+	Integrity      string
+	Media          string
+	Referrerpolicy ReferrerPolicy
+	// Icon sizes (e.g., "16x16 32x32").
+	Sizes string
+	Type  string
+}
+
+// Meter attributes.  Pointer fields are omitted if nil, since zero is a
+// meaningful bound; use Float() to set them.
+type MeterOptions struct {
+	High    *float64
+	Low     *float64
+	Max     *float64
+	Min     *float64
+	Optimum *float64
+	Value   float64
 }
 
 type MetaOptions struct {
@@ -112,6 +332,21 @@ type MetaOptions struct {
 	Name      string
 }
 
+type ObjectOptions struct {
+	Data string
+	Form string
+	// Height in pixels (non-zero to set)
+	Height int
+	Name   string
+	Type   string
+	// Width in pixels (non-zero to set)
+	Width int
+}
+
+type OptgroupOptions struct {
+	Disabled bool
+}
+
 type OptionOptions struct {
 	Disabled bool
 	Label    string
@@ -119,6 +354,33 @@ type OptionOptions struct {
 	Value    string
 }
 
+type OutputOptions struct {
+	// Ids of the elements that contributed to the output's value.
+	For  []string
+	Form string
+	Name string
+}
+
+type ProgressOptions struct {
+	// Only applied if > 0.
+	Max float64
+	// A nil Value creates an indeterminate progress bar.
+	Value *float64
+}
+
+// Options for NewRoot().
+type RootOptions struct {
+	Dir Dir
+	// The DOCTYPE declaration, such as DoctypeXhtml1Strict.  DoctypeHtml
+	// is used if empty.
+	Doctype string
+	Lang    string
+	// The application cache manifest URL.
+	Manifest string
+	// Omits the DOCTYPE declaration, e.g., for fragment rendering.
+	OmitDoctype bool
+}
+
 type SelectOptions struct {
 	Autofocus bool
 	Disabled  bool
@@ -128,11 +390,48 @@ type SelectOptions struct {
 	Size      int
 }
 
+// Script loading and security attributes.
+type ScriptOptions struct {
+	// Execute the script as soon as it is available.
+	Async       bool
+	Crossorigin CrossOrigin
+	// Execute the script after the document has been parsed.
+	Defer bool
+	// Subresource integrity metadata (e.g., "sha384-...").
+	Integrity string
+	// Load the script as a module.  This overrides the script type.
+	Module bool
+	// Skip the script in browsers that support modules.
+	Nomodule bool
+	// A cryptographic nonce for Content-Security-Policy.
+	Nonce          string
+	Referrerpolicy ReferrerPolicy
+}
+
 type StyleOptions struct {
 	Media string
+	// A cryptographic nonce for Content-Security-Policy.
+	Nonce string
 	Type  string
 }
 
+// Source attributes.  Src applies to <audio> and <video> sources, while
+// Media, Sizes and Srcset apply to <picture> sources.
+type SourceOptions struct {
+	Media string
+	Sizes string
+	Src   string
+	// Srcset is omitted if it is invalid; use Srcset.Validate() to check.
+	Srcset Srcset
+	Type   string
+}
+
+// A single inline style declaration.
+type styleProperty struct {
+	name  string
+	value string
+}
+
 type TableOptions struct {
 	Border bool
 }
@@ -154,6 +453,26 @@ type ThOptions struct {
 	Scope   string
 }
 
+// Optional track attributes.
+type TrackOptions struct {
+	Default bool
+	Kind    TrackKind
+	Label   string
+	Srclang string
+}
+
+// Declarative shadow root attributes for <template>.  The template is
+// an ordinary template if ShadowRootMode is empty.
+type TemplateOptions struct {
+	// Allow the shadow root to be cloned with its host.
+	Clonable bool
+	// Delegate focus from the host to the first focusable element.
+	DelegatesFocus bool
+	// Allow the shadow root to be serialized by getHTML().
+	Serializable   bool
+	ShadowRootMode ShadowRootMode
+}
+
 type TextareaOptions struct {
 	Autofocus   bool
 	Disabled    bool
@@ -166,6 +485,24 @@ type TextareaOptions struct {
 	WrapHard    bool
 }
 
+// Optional video attributes.
+type VideoOptions struct {
+	Autoplay bool
+	Controls bool
+	// Height in pixels (non-zero to set)
+	Height int
+	Loop   bool
+	Muted  bool
+	// Allows inline playback on mobile browsers.
+	Playsinline bool
+	// URL of an image to show until playback begins.
+	Poster  string
+	Preload Preload
+	Src     string
+	// Width in pixels (non-zero to set)
+	Width int
+}
+
 type htmlGen struct {
 }
 
@@ -173,17 +510,62 @@ func init() {
 	H = New()
 }
 
+// Returns a pointer to v for optional numeric fields, such as those of
+// MeterOptions.
+func Float(v float64) *float64 {
+	return &v
+}
+
+// Returns a pointer to v for optional integer fields, such as those of
+// InputOptions.
+func Int(v int) *int {
+	return &v
+}
+
 func New() TagFactory {
 	return &htmlGen{}
 }
 
-func NewRoot() Tag {
-	return &htmlTag{baseTag{
-		tagType:     kTagTypeHtml,
-		attrs:       make(map[int]string),
-		customAttrs: make(map[string]string),
-		children:    make([]tagWriter, 0),
-	}}
+// Creates a fragment root, which renders only its children.  This is
+// useful for partial responses, such as those requested by HTMX.
+func NewFragment() Tag {
+	return newNullTag()
+}
+
+// Creates an <html> root, which is preceded by a DOCTYPE declaration.
+// options is optional, and only the first one will be used.
+func NewRoot(options ...*RootOptions) Tag {
+	newTag := &htmlTag{
+		baseTag: baseTag{
+			tagType:     kTagTypeHtml,
+			attrs:       make(map[int]string),
+			customAttrs: make(map[string]string),
+			children:    make([]tagWriter, 0),
+		},
+		doctype: DoctypeHtml,
+	}
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if o.OmitDoctype {
+		newTag.doctype = ""
+	} else if len(o.Doctype) > 0 {
+		newTag.doctype = o.Doctype
+	}
+	if len(o.Dir) > 0 {
+		newTag.attrs[kAttrDir] = string(o.Dir)
+	}
+	if len(o.Lang) > 0 {
+		newTag.attrs[kAttrLang] = o.Lang
+	}
+	if len(o.Manifest) > 0 {
+		newTag.attrs[kAttrManifest] = o.Manifest
+	}
+
+	return newTag
 }
 
 // Creates a new null tag.
@@ -191,11 +573,52 @@ func NewNull() Tag {
 	return newNullTag()
 }
 
-func (t *htmlGen) A(href string) Tag {
+func (t *htmlGen) A(href string, options ...*AOptions) Tag {
 	newTag := newBaseTag(kTagTypeA)
 	if len(href) > 0 {
 		newTag.attrs[kAttrHref] = href
 	}
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if len(o.DownloadName) > 0 {
+		newTag.attrs[kAttrDownload] = o.DownloadName
+	} else if o.Download {
+		newTag.attrs[kAttrDownload] = ""
+	}
+
+	if len(o.Hreflang) > 0 {
+		newTag.attrs[kAttrHreflang] = o.Hreflang
+	}
+
+	if len(o.Ping) > 0 {
+		newTag.attrs[kAttrPing] = strings.Join(o.Ping, " ")
+	}
+
+	if len(o.Referrerpolicy) > 0 {
+		newTag.attrs[kAttrReferrerpolicy] = string(o.Referrerpolicy)
+	}
+
+	rel := o.Rel
+	if o.Target == TargetBlank {
+		// Prevent the opened page from accessing window.opener.
+		rel = append(rel[:len(rel):len(rel)], LinkRelNoopener, LinkRelNoreferrer)
+	}
+	if relStr := joinTokens(rel); len(relStr) > 0 {
+		newTag.attrs[kAttrRel] = relStr
+	}
+
+	if len(o.Target) > 0 {
+		newTag.attrs[kAttrTarget] = o.Target
+	}
+
+	if len(o.Type) > 0 {
+		newTag.attrs[kAttrType] = o.Type
+	}
+
 	return newTag
 }
 
@@ -211,10 +634,87 @@ func (t *htmlGen) Address() Tag {
 	return newBaseTag(kTagTypeAddress)
 }
 
+func (t *htmlGen) Article() Tag {
+	return newBaseTag(kTagTypeArticle)
+}
+
+func (t *htmlGen) ArticleClasses(classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeArticle, "", classes)
+}
+
+func (t *htmlGen) ArticleId(id string) Tag {
+	return newBaseTagIdClasses(kTagTypeArticle, id, nil)
+}
+
+func (t *htmlGen) ArticleIdClasses(id string, classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeArticle, id, classes)
+}
+
+func (t *htmlGen) Aside() Tag {
+	return newBaseTag(kTagTypeAside)
+}
+
+func (t *htmlGen) AsideClasses(classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeAside, "", classes)
+}
+
+func (t *htmlGen) AsideId(id string) Tag {
+	return newBaseTagIdClasses(kTagTypeAside, id, nil)
+}
+
+func (t *htmlGen) AsideIdClasses(id string, classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeAside, id, classes)
+}
+
+func (t *htmlGen) Audio(options ...*AudioOptions) Tag {
+	newTag := newBaseTag(kTagTypeAudio)
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if o.Autoplay {
+		newTag.attrs[kAttrAutoplay] = ""
+	}
+
+	if o.Controls {
+		newTag.attrs[kAttrControls] = ""
+	}
+
+	if o.Loop {
+		newTag.attrs[kAttrLoop] = ""
+	}
+
+	if o.Muted {
+		newTag.attrs[kAttrMuted] = ""
+	}
+
+	if len(o.Preload) > 0 {
+		newTag.attrs[kAttrPreload] = string(o.Preload)
+	}
+
+	if len(o.Src) > 0 {
+		newTag.attrs[kAttrSrc] = o.Src
+	}
+
+	return newTag
+}
+
 func (t *htmlGen) B() Tag {
 	return newBaseTag(kTagTypeB)
 }
 
+func (t *htmlGen) Bdi() Tag {
+	return newBaseTag(kTagTypeBdi)
+}
+
+func (t *htmlGen) Bdo(dir Dir) Tag {
+	newTag := newBaseTag(kTagTypeBdo)
+	newTag.setAttr(kAttrDir, string(dir))
+	return newTag
+}
+
 func (t *htmlGen) Blockquote() Tag {
 	return newBaseTag(kTagTypeBlockquote)
 }
@@ -287,10 +787,33 @@ func (t *htmlGen) Datalist() Tag {
 	return newBaseTag(kTagTypeDatalist)
 }
 
+func (t *htmlGen) DataValue(value string) Tag {
+	newTag := newBaseTag(kTagTypeData)
+	newTag.attrs[kAttrValue] = value
+	return newTag
+}
+
+func (t *htmlGen) Del(options ...*EditOptions) Tag {
+	return newEditTag(kTagTypeDel, options)
+}
+
+func (t *htmlGen) Details(open bool) *DetailsTag {
+	newTag := &DetailsTag{*newBaseTag(kTagTypeDetails)}
+	newTag.SetOpen(open)
+	return newTag
+}
+
 func (t *htmlGen) Dfn() Tag {
 	return newBaseTag(kTagTypeDfn)
 }
 
+func (t *htmlGen) Dialog(open, modal bool) *DialogTag {
+	newTag := &DialogTag{*newBaseTag(kTagTypeDialog)}
+	newTag.SetOpen(open)
+	newTag.SetModal(modal)
+	return newTag
+}
+
 func (t *htmlGen) Div() Tag {
 	return newBaseTag(kTagTypeDiv)
 }
@@ -326,10 +849,101 @@ func (t *htmlGen) Dt() Tag {
 	return newBaseTag(kTagTypeDt)
 }
 
+func (t *htmlGen) Element(name string) (Tag, error) {
+	if err := validateElementName(name); err != nil {
+		return nil, err
+	}
+	newTag := newBaseTag(kTagTypeCustom)
+	newTag.name = name
+	return newTag, nil
+}
+
 func (t *htmlGen) Em() Tag {
 	return newBaseTag(kTagTypeEm)
 }
 
+func (t *htmlGen) Embed(src, embedType string, options ...*EmbedOptions) Tag {
+	newTag := newSingleTag(kTagTypeEmbed)
+
+	if len(src) > 0 {
+		newTag.attrs[kAttrSrc] = src
+	}
+
+	if len(embedType) > 0 {
+		newTag.attrs[kAttrType] = embedType
+	}
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if o.Height > 0 {
+		newTag.attrs[kAttrHeight] = strconv.Itoa(o.Height)
+	}
+
+	if o.Width > 0 {
+		newTag.attrs[kAttrWidth] = strconv.Itoa(o.Width)
+	}
+
+	return newTag
+}
+
+func (t *htmlGen) Fieldset(options ...*FieldsetOptions) Tag {
+	newTag := newBaseTag(kTagTypeFieldset)
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if o.Disabled {
+		newTag.attrs[kAttrDisabled] = ""
+	}
+
+	if len(o.Form) > 0 {
+		newTag.attrs[kAttrForm] = o.Form
+	}
+
+	if len(o.Name) > 0 {
+		newTag.attrs[kAttrName] = o.Name
+	}
+
+	return newTag
+}
+
+func (t *htmlGen) Figcaption() Tag {
+	return newBaseTag(kTagTypeFigcaption)
+}
+
+func (t *htmlGen) FigcaptionClasses(classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeFigcaption, "", classes)
+}
+
+func (t *htmlGen) FigcaptionId(id string) Tag {
+	return newBaseTagIdClasses(kTagTypeFigcaption, id, nil)
+}
+
+func (t *htmlGen) FigcaptionIdClasses(id string, classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeFigcaption, id, classes)
+}
+
+func (t *htmlGen) Figure() Tag {
+	return newBaseTag(kTagTypeFigure)
+}
+
+func (t *htmlGen) FigureClasses(classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeFigure, "", classes)
+}
+
+func (t *htmlGen) FigureId(id string) Tag {
+	return newBaseTagIdClasses(kTagTypeFigure, id, nil)
+}
+
+func (t *htmlGen) FigureIdClasses(id string, classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeFigure, id, classes)
+}
+
 func (t *htmlGen) Footer() Tag {
 	return newBaseTag(kTagTypeFooter)
 }
@@ -400,6 +1014,38 @@ func (t *htmlGen) Head() Tag {
 	return newBaseTag(kTagTypeHead)
 }
 
+func (t *htmlGen) Header() Tag {
+	return newBaseTag(kTagTypeHeader)
+}
+
+func (t *htmlGen) HeaderClasses(classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeHeader, "", classes)
+}
+
+func (t *htmlGen) HeaderId(id string) Tag {
+	return newBaseTagIdClasses(kTagTypeHeader, id, nil)
+}
+
+func (t *htmlGen) HeaderIdClasses(id string, classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeHeader, id, classes)
+}
+
+func (t *htmlGen) Hgroup() Tag {
+	return newBaseTag(kTagTypeHgroup)
+}
+
+func (t *htmlGen) HgroupClasses(classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeHgroup, "", classes)
+}
+
+func (t *htmlGen) HgroupId(id string) Tag {
+	return newBaseTagIdClasses(kTagTypeHgroup, id, nil)
+}
+
+func (t *htmlGen) HgroupIdClasses(id string, classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeHgroup, id, classes)
+}
+
 func (t *htmlGen) Hr() Tag {
 	return newSingleTag(kTagTypeHr)
 }
@@ -408,6 +1054,63 @@ func (t *htmlGen) I() Tag {
 	return newBaseTag(kTagTypeI)
 }
 
+func (t *htmlGen) Iframe(src string, options ...*IframeOptions) Tag {
+	newTag := newBaseTag(kTagTypeIframe)
+
+	if len(src) > 0 {
+		newTag.attrs[kAttrSrc] = src
+	}
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if allow := o.Allow.String(); len(allow) > 0 {
+		newTag.attrs[kAttrAllow] = allow
+	}
+
+	if o.Allowfullscreen {
+		newTag.attrs[kAttrAllowfullscreen] = ""
+	}
+
+	if o.Height > 0 {
+		newTag.attrs[kAttrHeight] = strconv.Itoa(o.Height)
+	}
+
+	if len(o.Loading) > 0 {
+		newTag.attrs[kAttrLoading] = string(o.Loading)
+	}
+
+	if len(o.Name) > 0 {
+		newTag.attrs[kAttrName] = o.Name
+	}
+
+	if len(o.Referrerpolicy) > 0 {
+		newTag.attrs[kAttrReferrerpolicy] = string(o.Referrerpolicy)
+	}
+
+	if o.Sandbox || len(o.SandboxAllow) > 0 {
+		tokens := make([]string, len(o.SandboxAllow))
+		for ii, token := range o.SandboxAllow {
+			tokens[ii] = string(token)
+		}
+		newTag.attrs[kAttrSandbox] = strings.Join(tokens, " ")
+	}
+
+	newTag.srcdoc = o.Srcdoc
+
+	if len(o.Title) > 0 {
+		newTag.attrs[kAttrTitle] = o.Title
+	}
+
+	if o.Width > 0 {
+		newTag.attrs[kAttrWidth] = strconv.Itoa(o.Width)
+	}
+
+	return newTag
+}
+
 func (t *htmlGen) Img(src, alt string, options ...*ImgOptions) Tag {
 	newTag := newSingleTag(kTagTypeImg)
 
@@ -419,6 +1122,18 @@ func (t *htmlGen) Img(src, alt string, options ...*ImgOptions) Tag {
 	}
 
 	o := options[0]
+	if len(o.Crossorigin) > 0 {
+		newTag.attrs[kAttrCrossorigin] = string(o.Crossorigin)
+	}
+
+	if len(o.Decoding) > 0 {
+		newTag.attrs[kAttrDecoding] = string(o.Decoding)
+	}
+
+	if len(o.Fetchpriority) > 0 {
+		newTag.attrs[kAttrFetchpriority] = string(o.Fetchpriority)
+	}
+
 	if o.Height > 0 {
 		newTag.attrs[kAttrHeight] = strconv.Itoa(o.Height)
 	}
@@ -427,6 +1142,22 @@ func (t *htmlGen) Img(src, alt string, options ...*ImgOptions) Tag {
 		newTag.attrs[kAttrIsmap] = ""
 	}
 
+	if len(o.Loading) > 0 {
+		newTag.attrs[kAttrLoading] = string(o.Loading)
+	}
+
+	if len(o.Referrerpolicy) > 0 {
+		newTag.attrs[kAttrReferrerpolicy] = string(o.Referrerpolicy)
+	}
+
+	if len(o.Sizes) > 0 {
+		newTag.attrs[kAttrSizes] = o.Sizes
+	}
+
+	if len(o.Srcset) > 0 && o.Srcset.Validate() == nil {
+		newTag.attrs[kAttrSrcset] = o.Srcset.String()
+	}
+
 	if len(o.Usemap) > 0 {
 		newTag.attrs[kAttrUsemap] = o.Usemap
 	}
@@ -473,6 +1204,10 @@ func (t *htmlGen) InputTypeNameValue(inputType InputType, name, value string) *I
 	return newTag
 }
 
+func (t *htmlGen) Ins(options ...*EditOptions) Tag {
+	return newEditTag(kTagTypeIns, options)
+}
+
 func (t *htmlGen) Kbd() Tag {
 	return newBaseTag(kTagTypeKbd)
 }
@@ -497,6 +1232,14 @@ func (t *htmlGen) Label(options ...*LabelOptions) Tag {
 	return newTag
 }
 
+func (t *htmlGen) Legend(text ...string) Tag {
+	newTag := newBaseTag(kTagTypeLegend)
+	if len(text) > 0 {
+		newTag.T(text[0])
+	}
+	return newTag
+}
+
 func (t *htmlGen) Li() Tag {
 	return newBaseTag(kTagTypeLi)
 }
@@ -512,74 +1255,327 @@ func (t *htmlGen) Link(rel string, options ...*LinkOptions) Tag {
 	}
 
 	o := options[0]
+	if len(o.As) > 0 {
+		newTag.attrs[kAttrAs] = string(o.As)
+	}
+	if len(o.Crossorigin) > 0 {
+		newTag.attrs[kAttrCrossorigin] = string(o.Crossorigin)
+	}
 	if len(o.Href) > 0 {
 		newTag.attrs[kAttrHref] = o.Href
 	}
 	if len(o.Hreflang) > 0 {
 		newTag.attrs[kAttrHreflang] = o.Hreflang
 	}
+	if len(o.Imagesizes) > 0 {
+		newTag.attrs[kAttrImagesizes] = o.Imagesizes
+	}
+	if len(o.Imagesrcset) > 0 && o.Imagesrcset.Validate() == nil {
+		newTag.attrs[kAttrImagesrcset] = o.Imagesrcset.String()
+	}
+	if len(o.Integrity) > 0 {
+		newTag.attrs[kAttrIntegrity] = o.Integrity
+	}
 	if len(o.Media) > 0 {
 		newTag.attrs[kAttrMedia] = o.Media
 	}
+	if len(o.Referrerpolicy) > 0 {
+		newTag.attrs[kAttrReferrerpolicy] = string(o.Referrerpolicy)
+	}
+	if len(o.Sizes) > 0 {
+		newTag.attrs[kAttrSizes] = o.Sizes
+	}
 	if len(o.Type) > 0 {
 		newTag.attrs[kAttrType] = o.Type
 	}
 	return newTag
 }
 
+func (t *htmlGen) Main() Tag {
+	return newBaseTag(kTagTypeMain)
+}
+
+func (t *htmlGen) MainClasses(classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeMain, "", classes)
+}
+
+func (t *htmlGen) MainId(id string) Tag {
+	return newBaseTagIdClasses(kTagTypeMain, id, nil)
+}
+
+func (t *htmlGen) MainIdClasses(id string, classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeMain, id, classes)
+}
+
+func (t *htmlGen) Mark() Tag {
+	return newBaseTag(kTagTypeMark)
+}
+
+// Creates a root <math> element with a MathML xmlns declaration.
+func (t *htmlGen) Math(options ...*MathOptions) *MathTag {
+	newTag := newMathTag("math")
+	newTag.SetAttribute("xmlns", NamespaceMathml)
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if len(o.Alttext) > 0 {
+		newTag.SetAttribute("alttext", o.Alttext)
+	}
+	if len(o.Display) > 0 {
+		newTag.SetAttribute("display", string(o.Display))
+	}
+
+	return newTag
+}
+
+func (t *htmlGen) Menu() Tag {
+	return newBaseTag(kTagTypeMenu)
+}
+
 func (t *htmlGen) Meta(name, content string, options ...*MetaOptions) Tag {
 	newTag := newSingleTag(kTagTypeMeta)
 
-	newTag.attrs[kAttrName] = name
-	newTag.attrs[kAttrContent] = content
+	newTag.attrs[kAttrName] = name
+	newTag.attrs[kAttrContent] = content
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if len(o.Charset) > 0 {
+		newTag.attrs[kAttrCharset] = o.Charset
+	}
+	if len(o.Content) > 0 {
+		newTag.attrs[kAttrContent] = o.Content
+	}
+	if len(o.HttpEquiv) > 0 {
+		newTag.attrs[kAttrHttpEquiv] = o.HttpEquiv
+	}
+	if len(o.Name) > 0 {
+		newTag.attrs[kAttrName] = o.Name
+	}
+	return newTag
+}
+
+func (t *htmlGen) Meter(options ...*MeterOptions) Tag {
+	newTag := newBaseTag(kTagTypeMeter)
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if o.High != nil {
+		newTag.attrs[kAttrHigh] = formatFloat(*o.High)
+	}
+
+	if o.Low != nil {
+		newTag.attrs[kAttrLow] = formatFloat(*o.Low)
+	}
+
+	if o.Max != nil {
+		newTag.attrs[kAttrMax] = formatFloat(*o.Max)
+	}
+
+	if o.Min != nil {
+		newTag.attrs[kAttrMin] = formatFloat(*o.Min)
+	}
+
+	if o.Optimum != nil {
+		newTag.attrs[kAttrOptimum] = formatFloat(*o.Optimum)
+	}
+
+	// value is required.
+	newTag.attrs[kAttrValue] = formatFloat(o.Value)
+
+	return newTag
+}
+
+func (t *htmlGen) Nav() Tag {
+	return newBaseTag(kTagTypeNav)
+}
+
+func (t *htmlGen) NavClasses(classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeNav, "", classes)
+}
+
+func (t *htmlGen) NavId(id string) Tag {
+	return newBaseTagIdClasses(kTagTypeNav, id, nil)
+}
+
+func (t *htmlGen) NavIdClasses(id string, classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeNav, id, classes)
+}
+
+func (t *htmlGen) NoScript() Tag {
+	return newBaseTag(kTagTypeNoScript)
+}
+
+func (t *htmlGen) Object(options ...*ObjectOptions) Tag {
+	newTag := newBaseTag(kTagTypeObject)
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if len(o.Data) > 0 {
+		newTag.attrs[kAttrData] = o.Data
+	}
+
+	if len(o.Form) > 0 {
+		newTag.attrs[kAttrForm] = o.Form
+	}
+
+	if o.Height > 0 {
+		newTag.attrs[kAttrHeight] = strconv.Itoa(o.Height)
+	}
+
+	if len(o.Name) > 0 {
+		newTag.attrs[kAttrName] = o.Name
+	}
+
+	if len(o.Type) > 0 {
+		newTag.attrs[kAttrType] = o.Type
+	}
+
+	if o.Width > 0 {
+		newTag.attrs[kAttrWidth] = strconv.Itoa(o.Width)
+	}
+
+	return newTag
+}
+
+func (t *htmlGen) Ol() Tag {
+	return newBaseTag(kTagTypeOl)
+}
+
+func (t *htmlGen) Optgroup(label string, options ...*OptgroupOptions) *OptgroupTag {
+	newTag := &OptgroupTag{*newBaseTag(kTagTypeOptgroup)}
+
+	// label is required.
+	newTag.attrs[kAttrLabel] = label
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if o.Disabled {
+		newTag.attrs[kAttrDisabled] = ""
+	}
+
+	return newTag
+}
+
+func (t *htmlGen) Option(options ...*OptionOptions) *OptionTag {
+	newTag := &OptionTag{*newBaseTag(kTagTypeOption)}
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	newTag.SetOptions(o)
+
+	return newTag
+}
+
+func (t *htmlGen) Output(options ...*OutputOptions) Tag {
+	newTag := newBaseTag(kTagTypeOutput)
 
 	if len(options) == 0 {
 		return newTag
 	}
 
 	o := options[0]
-	if len(o.Charset) > 0 {
-		newTag.attrs[kAttrCharset] = o.Charset
-	}
-	if len(o.Content) > 0 {
-		newTag.attrs[kAttrContent] = o.Content
+	if len(o.For) > 0 {
+		newTag.attrs[kAttrFor] = strings.Join(o.For, " ")
 	}
-	if len(o.HttpEquiv) > 0 {
-		newTag.attrs[kAttrHttpEquiv] = o.HttpEquiv
+
+	if len(o.Form) > 0 {
+		newTag.attrs[kAttrForm] = o.Form
 	}
+
 	if len(o.Name) > 0 {
 		newTag.attrs[kAttrName] = o.Name
 	}
+
 	return newTag
 }
 
-func (t *htmlGen) NoScript() Tag {
-	return newBaseTag(kTagTypeNoScript)
+func (t *htmlGen) P() Tag {
+	return newBaseTag(kTagTypeP)
 }
 
-func (t *htmlGen) Ol() Tag {
-	return newBaseTag(kTagTypeOl)
+func (t *htmlGen) Param(name, value string) Tag {
+	newTag := newSingleTag(kTagTypeParam)
+	newTag.attrs[kAttrName] = name
+	newTag.attrs[kAttrValue] = value
+	return newTag
 }
 
-func (t *htmlGen) Option(options ...*OptionOptions) *OptionTag {
-	newTag := &OptionTag{*newBaseTag(kTagTypeOption)}
+func (t *htmlGen) Picture(src, alt string, sources []*SourceOptions, options ...*ImgOptions) Tag {
+	newTag := newBaseTag(kTagTypePicture)
+
+	// Sources must precede the fallback img.
+	for _, o := range sources {
+		addChild(newTag, t.Source(o))
+	}
+	addChild(newTag, t.Img(src, alt, options...))
+
+	return newTag
+}
+
+func (t *htmlGen) Pre() Tag {
+	return newBaseTag(kTagTypePre)
+}
+
+func (t *htmlGen) Progress(options ...*ProgressOptions) Tag {
+	newTag := newBaseTag(kTagTypeProgress)
 
 	if len(options) == 0 {
 		return newTag
 	}
 
 	o := options[0]
-	newTag.SetOptions(o)
+	if o.Max > 0 {
+		newTag.attrs[kAttrMax] = formatFloat(o.Max)
+	}
+
+	if o.Value != nil {
+		newTag.attrs[kAttrValue] = formatFloat(*o.Value)
+	}
 
 	return newTag
 }
 
-func (t *htmlGen) P() Tag {
-	return newBaseTag(kTagTypeP)
+func (t *htmlGen) Q(cite ...string) Tag {
+	newTag := newBaseTag(kTagTypeQ)
+	if len(cite) > 0 {
+		newTag.setAttr(kAttrCite, cite[0])
+	}
+	return newTag
 }
 
-func (t *htmlGen) Pre() Tag {
-	return newBaseTag(kTagTypePre)
+func (t *htmlGen) Rp() Tag {
+	return newBaseTag(kTagTypeRp)
+}
+
+func (t *htmlGen) Rt() Tag {
+	return newBaseTag(kTagTypeRt)
+}
+
+func (t *htmlGen) Ruby() Tag {
+	return newBaseTag(kTagTypeRuby)
+}
+
+func (t *htmlGen) S() Tag {
+	return newBaseTag(kTagTypeS)
 }
 
 func (t *htmlGen) Samp() Tag {
@@ -594,7 +1590,19 @@ func (t *htmlGen) Script(optScriptType ...string) Tag {
 	return t.ScriptSrc(scriptType, "")
 }
 
-func (t *htmlGen) ScriptSrc(scriptType, src string) Tag {
+// Returns a <script type="importmap"> whose body is the JSON encoding of m.
+// ScriptOptions.Nonce is needed for pages with a strict
+// Content-Security-Policy.
+func (t *htmlGen) ScriptImportMap(m *ImportMap, options ...*ScriptOptions) Tag {
+	newTag := t.ScriptSrc(ScriptTypeImportmap, "", options...)
+	// The encoder escapes '<', '>' and '&', so the body can never contain
+	// "</script>".  A map of strings always encodes.
+	body, _ := json.Marshal(m)
+	newTag.TUnsafe(string(body))
+	return newTag
+}
+
+func (t *htmlGen) ScriptSrc(scriptType, src string, options ...*ScriptOptions) Tag {
 	newTag := newBaseTag(kTagTypeScript)
 	if len(scriptType) > 0 {
 		newTag.attrs[kAttrType] = scriptType
@@ -602,14 +1610,74 @@ func (t *htmlGen) ScriptSrc(scriptType, src string) Tag {
 	if len(src) > 0 {
 		newTag.attrs[kAttrSrc] = src
 	}
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if o.Async {
+		newTag.attrs[kAttrAsync] = ""
+	}
+	if len(o.Crossorigin) > 0 {
+		newTag.attrs[kAttrCrossorigin] = string(o.Crossorigin)
+	}
+	if o.Defer {
+		newTag.attrs[kAttrDefer] = ""
+	}
+	if len(o.Integrity) > 0 {
+		newTag.attrs[kAttrIntegrity] = o.Integrity
+	}
+	if o.Module {
+		newTag.attrs[kAttrType] = ScriptTypeModule
+	}
+	if o.Nomodule {
+		newTag.attrs[kAttrNomodule] = ""
+	}
+	if len(o.Nonce) > 0 {
+		newTag.attrs[kAttrNonce] = o.Nonce
+	}
+	if len(o.Referrerpolicy) > 0 {
+		newTag.attrs[kAttrReferrerpolicy] = string(o.Referrerpolicy)
+	}
+
 	return newTag
 }
 
+func (t *htmlGen) Search() Tag {
+	return newBaseTag(kTagTypeSearch)
+}
+
+func (t *htmlGen) SearchClasses(classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeSearch, "", classes)
+}
+
+func (t *htmlGen) SearchId(id string) Tag {
+	return newBaseTagIdClasses(kTagTypeSearch, id, nil)
+}
+
+func (t *htmlGen) SearchIdClasses(id string, classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeSearch, id, classes)
+}
+
+func (t *htmlGen) Section() Tag {
+	return newBaseTag(kTagTypeSection)
+}
+
+func (t *htmlGen) SectionClasses(classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeSection, "", classes)
+}
+
+func (t *htmlGen) SectionId(id string) Tag {
+	return newBaseTagIdClasses(kTagTypeSection, id, nil)
+}
+
+func (t *htmlGen) SectionIdClasses(id string, classes ...string) Tag {
+	return newBaseTagIdClasses(kTagTypeSection, id, classes)
+}
+
 func (t *htmlGen) Select(options ...*SelectOptions) *SelectTag {
-	newTag := &SelectTag{
-		baseTag: *newBaseTag(kTagTypeSelect),
-		options: make([]*OptionTag, 0),
-	}
+	newTag := &SelectTag{*newBaseTag(kTagTypeSelect)}
 
 	if len(options) == 0 {
 		return newTag
@@ -644,10 +1712,49 @@ func (t *htmlGen) Select(options ...*SelectOptions) *SelectTag {
 	return newTag
 }
 
+// Creates a <slot> for a shadow tree.  name is omitted if empty, which
+// creates the default slot.
+func (t *htmlGen) Slot(name string) Tag {
+	newTag := newBaseTag(kTagTypeSlot)
+	newTag.setAttr(kAttrName, name)
+	return newTag
+}
+
 func (t *htmlGen) Small() Tag {
 	return newBaseTag(kTagTypeSmall)
 }
 
+func (t *htmlGen) Source(options ...*SourceOptions) Tag {
+	newTag := newSingleTag(kTagTypeSource)
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if len(o.Media) > 0 {
+		newTag.attrs[kAttrMedia] = o.Media
+	}
+
+	if len(o.Sizes) > 0 {
+		newTag.attrs[kAttrSizes] = o.Sizes
+	}
+
+	if len(o.Src) > 0 {
+		newTag.attrs[kAttrSrc] = o.Src
+	}
+
+	if len(o.Srcset) > 0 && o.Srcset.Validate() == nil {
+		newTag.attrs[kAttrSrcset] = o.Srcset.String()
+	}
+
+	if len(o.Type) > 0 {
+		newTag.attrs[kAttrType] = o.Type
+	}
+
+	return newTag
+}
+
 func (t *htmlGen) Span() Tag {
 	return newBaseTag(kTagTypeSpan)
 }
@@ -687,6 +1794,9 @@ func (t *htmlGen) Style(options ...*StyleOptions) Tag {
 	if len(o.Media) > 0 {
 		newTag.attrs[kAttrMedia] = o.Media
 	}
+	if len(o.Nonce) > 0 {
+		newTag.attrs[kAttrNonce] = o.Nonce
+	}
 	if len(o.Type) > 0 {
 		newTag.attrs[kAttrType] = o.Type
 	}
@@ -694,6 +1804,50 @@ func (t *htmlGen) Style(options ...*StyleOptions) Tag {
 	return newTag
 }
 
+func (t *htmlGen) Sub() Tag {
+	return newBaseTag(kTagTypeSub)
+}
+
+func (t *htmlGen) Summary(text ...string) Tag {
+	newTag := newBaseTag(kTagTypeSummary)
+	if len(text) > 0 {
+		newTag.T(text[0])
+	}
+	return newTag
+}
+
+func (t *htmlGen) Sup() Tag {
+	return newBaseTag(kTagTypeSup)
+}
+
+// Creates a root <svg> element.  Its xmlns declarations allow the output
+// to be used as a standalone SVG document.
+func (t *htmlGen) Svg(options ...*SvgOptions) *SvgTag {
+	newTag := newSvgTag("svg").
+		setSvgAttr("xmlns", NamespaceSvg).
+		setSvgAttr("xmlns:xlink", NamespaceXlink)
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if o.Height != 0 {
+		newTag.setSvgAttr("height", formatFloat(o.Height))
+	}
+	if len(o.PreserveAspectRatio) > 0 {
+		newTag.setSvgAttr("preserveAspectRatio", o.PreserveAspectRatio)
+	}
+	if o.ViewBox != nil {
+		newTag.SetViewBox(*o.ViewBox)
+	}
+	if o.Width != 0 {
+		newTag.setSvgAttr("width", formatFloat(o.Width))
+	}
+
+	return newTag
+}
+
 func (t *htmlGen) T(text ...string) *TextTag {
 	textStr := ""
 	if len(text) > 0 {
@@ -750,6 +1904,29 @@ func (t *htmlGen) Td(options ...*TdOptions) Tag {
 	return newTag
 }
 
+func (t *htmlGen) Template(options ...*TemplateOptions) Tag {
+	newTag := newBaseTag(kTagTypeTemplate)
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if len(o.ShadowRootMode) == 0 {
+		return newTag
+	}
+	newTag.attrs[kAttrShadowrootmode] = string(o.ShadowRootMode)
+	if o.Clonable {
+		newTag.attrs[kAttrShadowrootclonable] = ""
+	}
+	if o.DelegatesFocus {
+		newTag.attrs[kAttrShadowrootdelegatesfocus] = ""
+	}
+	if o.Serializable {
+		newTag.attrs[kAttrShadowrootserializable] = ""
+	}
+	return newTag
+}
+
 func (t *htmlGen) Textarea(rows, cols int, options ...*TextareaOptions) Tag {
 	newTag := newBaseTag(kTagTypeTextarea)
 	newTag.attrs[kAttrRows] = strconv.Itoa(rows)
@@ -836,6 +2013,15 @@ func (t *htmlGen) Thead() Tag {
 	return newBaseTag(kTagTypeThead)
 }
 
+func (t *htmlGen) Time(tm time.Time, layout string) Tag {
+	newTag := newBaseTag(kTagTypeTime)
+	newTag.attrs[kAttrDatetime] = formatDatetime(tm)
+	if len(layout) > 0 {
+		newTag.T(tm.Format(layout))
+	}
+	return newTag
+}
+
 func (t *htmlGen) Title() Tag {
 	return newBaseTag(kTagTypeTitle)
 }
@@ -844,13 +2030,44 @@ func (t *htmlGen) Tr() Tag {
 	return newBaseTag(kTagTypeTr)
 }
 
+func (t *htmlGen) Track(src string, options ...*TrackOptions) Tag {
+	newTag := newSingleTag(kTagTypeTrack)
+
+	// src is required.
+	newTag.attrs[kAttrSrc] = src
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if o.Default {
+		newTag.attrs[kAttrDefault] = ""
+	}
+
+	if len(o.Kind) > 0 {
+		newTag.attrs[kAttrKind] = string(o.Kind)
+	}
+
+	if len(o.Label) > 0 {
+		newTag.attrs[kAttrLabel] = o.Label
+	}
+
+	if len(o.Srclang) > 0 {
+		newTag.attrs[kAttrSrclang] = o.Srclang
+	}
+
+	return newTag
+}
+
 func (t *htmlGen) TUnsafe(text ...string) *TextTag {
 	textStr := ""
 	if len(text) > 0 {
 		textStr = text[0]
 	}
 	return &TextTag{
-		text: textStr,
+		text:     textStr,
+		isUnsafe: true,
 	}
 }
 
@@ -884,6 +2101,96 @@ func (t *htmlGen) Var() Tag {
 	return newBaseTag(kTagTypeVar)
 }
 
+func (t *htmlGen) Video(options ...*VideoOptions) Tag {
+	newTag := newBaseTag(kTagTypeVideo)
+
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if o.Autoplay {
+		newTag.attrs[kAttrAutoplay] = ""
+	}
+
+	if o.Controls {
+		newTag.attrs[kAttrControls] = ""
+	}
+
+	if o.Height > 0 {
+		newTag.attrs[kAttrHeight] = strconv.Itoa(o.Height)
+	}
+
+	if o.Loop {
+		newTag.attrs[kAttrLoop] = ""
+	}
+
+	if o.Muted {
+		newTag.attrs[kAttrMuted] = ""
+	}
+
+	if o.Playsinline {
+		newTag.attrs[kAttrPlaysinline] = ""
+	}
+
+	if len(o.Poster) > 0 {
+		newTag.attrs[kAttrPoster] = o.Poster
+	}
+
+	if len(o.Preload) > 0 {
+		newTag.attrs[kAttrPreload] = string(o.Preload)
+	}
+
+	if len(o.Src) > 0 {
+		newTag.attrs[kAttrSrc] = o.Src
+	}
+
+	if o.Width > 0 {
+		newTag.attrs[kAttrWidth] = strconv.Itoa(o.Width)
+	}
+
+	return newTag
+}
+
+func (t *htmlGen) VoidElement(name string) (Tag, error) {
+	if !voidElementNames[name] {
+		return nil, ErrInvalidElementName
+	}
+	newTag := newSingleTag(kTagTypeCustom)
+	newTag.name = name
+	return newTag, nil
+}
+
+func (t *htmlGen) Wbr() Tag {
+	return newSingleTag(kTagTypeWbr)
+}
+
+// Creates an <ins> or <del> element from the first of options, if any.
+func newEditTag(tagType int, options []*EditOptions) *baseTag {
+	newTag := newBaseTag(tagType)
+	if len(options) == 0 {
+		return newTag
+	}
+
+	o := options[0]
+	if len(o.Cite) > 0 {
+		newTag.attrs[kAttrCite] = o.Cite
+	}
+	if !o.Datetime.IsZero() {
+		newTag.attrs[kAttrDatetime] = formatDatetime(o.Datetime)
+	}
+	return newTag
+}
+
+// Creates a new baseTag with the given id and classes, either of which may be
+// empty.
+func newBaseTagIdClasses(tagType int, id string, classes []string) *baseTag {
+	newTag := newBaseTag(tagType)
+	newTag.SetClasses(classes)
+	newTag.SetId(id)
+	return newTag
+}
+
 // Returns a copy of attrs.
 func copyAttrs(attrs map[int]string) map[int]string {
 	dest := make(map[int]string, len(attrs))
@@ -893,6 +2200,26 @@ func copyAttrs(attrs map[int]string) map[int]string {
 	return dest
 }
 
+// Returns a copy of classes.
+func copyClasses(classes []string) []string {
+	if len(classes) == 0 {
+		return nil
+	}
+	dest := make([]string, len(classes))
+	copy(dest, classes)
+	return dest
+}
+
+// Returns a copy of styles.
+func copyStyles(styles []styleProperty) []styleProperty {
+	if len(styles) == 0 {
+		return nil
+	}
+	dest := make([]styleProperty, len(styles))
+	copy(dest, styles)
+	return dest
+}
+
 func copyCustomAttrs(attrs map[string]string) map[string]string {
 	dest := make(map[string]string, len(attrs))
 	for k, v := range attrs {
@@ -901,23 +2228,143 @@ func copyCustomAttrs(attrs map[string]string) map[string]string {
 	return dest
 }
 
-// Writes the tag tree from the given root tag.
-// env is an optional environment
+// Returns the data attribute name for a SetData() key, converting camelCase
+// to kebab-case as the dataset API does, or false if key is invalid.
+func dataAttrName(key string) (string, bool) {
+	buf := new(bytes.Buffer)
+	buf.WriteString(kAttrPrefixData)
+	for _, r := range key {
+		if 'A' <= r && r <= 'Z' {
+			buf.WriteByte('-')
+			r += 'a' - 'A'
+		}
+		buf.WriteRune(r)
+	}
+
+	name := buf.String()
+	if !reDataAttr.MatchString(name) {
+		return "", false
+	}
+	return name, true
+}
+
+// Returns the canonical form of a CSS property name.  Names are
+// case-insensitive except for custom properties, which start with "--".
+func cssPropertyName(prop string) string {
+	if strings.HasPrefix(prop, "--") {
+		return prop
+	}
+	return strings.ToLower(prop)
+}
+
+// Returns true if value cannot end its declaration or rule: ';', '{', '}'
+// and comments are only allowed in strings, which must be terminated.
+func isCssValue(value string) bool {
+	var quote rune
+	escaped := false
+	prev := rune(0)
+	for _, ch := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case ch == '\\':
+			escaped = true
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else if ch == '\n' || ch == '\r' || ch == '\f' {
+				// Strings cannot span lines.
+				return false
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == ';' || ch == '{' || ch == '}', prev == '/' && ch == '*':
+			return false
+		}
+		prev = ch
+	}
+	return quote == 0 && !escaped
+}
+
+// Writes root to writer in RenderModeXhtml.  env is optional, and only the
+// first one will be used.  See also Render().
 func Write(writer io.Writer, root Tag, env ...Environment) (int, error) {
-	return root.write(writer, env...)
+	return root.write(writer, newRenderContext(envOptions(env)))
+}
+
+// Joins the non-empty tokens with spaces, omitting duplicates.
+func joinTokens(tokens []string) string {
+	result := make([]string, 0, len(tokens))
+	seen := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		if len(token) == 0 || seen[token] {
+			continue
+		}
+		seen[token] = true
+		result = append(result, token)
+	}
+	return strings.Join(result, " ")
+}
+
+// Formats tm as a valid global date and time string.  Fractional seconds are
+// truncated to milliseconds, the maximum precision that HTML permits.
+func formatDatetime(tm time.Time) string {
+	if tm.Nanosecond()/int(time.Millisecond) == 0 {
+		return tm.Format(time.RFC3339)
+	}
+	return tm.Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// Returns ErrInvalidElementName if name cannot be used with Element().
+func validateElementName(name string) error {
+	if !reElementName.MatchString(name) || reservedElementNames[name] {
+		return ErrInvalidElementName
+	}
+	return nil
+}
+
+// Formats v in the shortest form that represents it exactly.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Formats b as an HTML "true" or "false" enumerated attribute value.
+func formatBool(b bool) string {
+	if b {
+		return kHtmlTrue
+	}
+	return kHtmlFalse
 }
 
-func writeIndent(writer io.Writer, indent int) (int, error) {
-	indentStr := strings.Repeat(" ", indent)
+// Writes indent levels of indentation.
+func writeIndent(writer io.Writer, indent int, ctx *renderContext) (int, error) {
+	indentStr := strings.Repeat(ctx.indent, indent)
 	return io.WriteString(writer, indentStr)
 }
 
-func writeKeyValue(writer io.Writer, key, value string) (n int, err error) {
+// Writes an attribute.  Empty values are minimized in RenderModeHtml,
+// values are unquoted when safe in RenderModeHtmlMinified, and values are
+// made well-formed in RenderModeXml.
+func writeKeyValue(writer io.Writer, key, value string, mode RenderMode) (n int, err error) {
 	if count, err := io.WriteString(writer, key); err != nil {
 		return n, err
 	} else {
 		n += count
 	}
+	if len(value) == 0 && mode.isHtml() {
+		return
+	}
+	if mode == RenderModeHtmlMinified && isUnquotedAttrValue(value) {
+		if count, err := io.WriteString(writer, "="+value); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
+		return
+	}
+	if mode == RenderModeXml {
+		value = xmlAttrValue(value)
+	}
 	if count, err := io.WriteString(writer, "=\""); err != nil {
 		return n, err
 	} else {
@@ -937,13 +2384,23 @@ func writeKeyValue(writer io.Writer, key, value string) (n int, err error) {
 }
 
 // Writes HTML 'key="value"' tag attributes.  keyId is a kAttr constant.
-func writeKeyIdValue(writer io.Writer, keyId int, value string) (int, error) {
+func writeKeyIdValue(writer io.Writer, keyId int, value string, mode RenderMode) (int, error) {
 	keyStr := attrStringMap[keyId]
-	return writeKeyValue(writer, keyStr, value)
+	return writeKeyValue(writer, keyStr, value, mode)
+}
+
+// Like Write() but for the smallest output.  See RenderModeHtmlMinified.
+func WriteMinified(writer io.Writer, root Tag, env ...Environment) (int, error) {
+	options := &RenderOptions{Mode: RenderModeHtmlMinified}
+	if len(env) > 0 {
+		options.Env = env[0]
+	}
+	return Render(writer, root, options)
 }
 
+// Like Write() but with indentation.  See also RenderPretty().
 func WritePretty(writer io.Writer, root Tag, env ...Environment) (int, error) {
-	return root.writePretty(writer, 0, env...)
+	return RenderPretty(writer, root, envOptions(env))
 }
 
 func writeRune(writer io.Writer, ch rune) (int, error) {
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func compareHtml(root Tag, cmp string, usePretty bool, env ...Environment) error {
@@ -37,6 +38,96 @@ func compareHtml(root Tag, cmp string, usePretty bool, env ...Environment) error
 	return nil
 }
 
+func Test_Anchor(t *testing.T) {
+	const kCompare = `<body>
+  <a download="" href="a.pdf" ping="/track" rel="nofollow noopener noreferrer" target="_blank" type="application/pdf">
+    PDF
+  </a>
+  <a download="report.csv" href="/export" hreflang="en" referrerpolicy="origin">
+    CSV
+  </a>
+  <link as="image" crossorigin="anonymous" href="hero.jpg" imagesizes="50vw" imagesrcset="hero-480.jpg 480w, hero-960.jpg 960w" rel="preload" />
+  <link href="app.js" integrity="sha384-abc" rel="modulepreload" />
+  <link href="icon.png" rel="icon" sizes="16x16 32x32" />
+</body>`
+
+	body := H.Body()
+	body.A("a.pdf", &AOptions{
+		Download: true,
+		Ping:     []string{"/track"},
+		Rel:      []string{LinkRelNofollow, LinkRelNoopener},
+		Target:   TargetBlank,
+		Type:     "application/pdf",
+	}).T("PDF")
+	body.A("/export", &AOptions{
+		Download:       true,
+		DownloadName:   "report.csv",
+		Hreflang:       "en",
+		Referrerpolicy: ReferrerPolicyOrigin,
+	}).T("CSV")
+	body.Link(LinkRelPreload, &LinkOptions{
+		As:          LinkAsImage,
+		Crossorigin: CrossOriginAnonymous,
+		Href:        "hero.jpg",
+		Imagesizes:  "50vw",
+		Imagesrcset: Srcset{{URL: "hero-480.jpg", Width: 480}, {URL: "hero-960.jpg", Width: 960}},
+	})
+	body.Link(LinkRelModulepreload, &LinkOptions{Href: "app.js", Integrity: "sha384-abc"})
+	body.Link(LinkRelIcon, &LinkOptions{Href: "icon.png", Sizes: "16x16 32x32"})
+
+	if err := compareHtml(body, kCompare, true); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Classes(t *testing.T) {
+	tag := H.Div().AddClass("foo", "bar foo").AddClass("baz")
+	if err := compare(t, tag, `<div class="foo bar baz"></div>`); err != nil {
+		t.Error(err)
+	}
+	if !tag.HasClass("bar") || tag.HasClass("qux") {
+		t.Errorf("unexpected HasClass result for %v", tag.Classes())
+	}
+
+	tag.RemoveClass("bar", "missing")
+	if err := compare(t, tag, `<div class="foo baz"></div>`); err != nil {
+		t.Error(err)
+	}
+
+	tag.ToggleClass("active", true).ToggleClass("foo", false)
+	if err := compare(t, tag, `<div class="baz active"></div>`); err != nil {
+		t.Error(err)
+	}
+
+	tag.ToggleClasses(ClassMap{"selected": true, "disabled": true, "active": false})
+	if err := compare(t, tag, `<div class="baz disabled selected"></div>`); err != nil {
+		t.Error(err)
+	}
+
+	// Classes() must return a copy.
+	classes := tag.Classes()
+	classes[0] = "changed"
+	if tag.Classes()[0] != "baz" {
+		t.Errorf("Classes() returned internal state")
+	}
+
+	// The copy must not share class state with the original.
+	tagCopy := tag.Copy().AddClass("copy")
+	if tag.HasClass("copy") || !tagCopy.HasClass("baz") {
+		t.Errorf("copy shares classes with original")
+	}
+
+	tag.RemoveClass("baz", "disabled", "selected")
+	if err := compare(t, tag, `<div></div>`); err != nil {
+		t.Error(err)
+	}
+
+	tag.SetClass("one two", "one")
+	if err := compare(t, tag, `<div class="one two"></div>`); err != nil {
+		t.Error(err)
+	}
+}
+
 func Test_Copy(t *testing.T) {
 	const kCompare = `<div class="foo"></div>`
 	const kCopyCompare = `<div class="foo" id="copy"></div>`
@@ -110,6 +201,143 @@ func Test_Copy(t *testing.T) {
 	}
 }
 
+func Test_DataAria(t *testing.T) {
+	tag := H.Div().SetData("userId", "42").SetData("empty", "").SetRole("dialog")
+	tag.SetAriaExpanded(false).SetAriaHidden(true).SetAriaLabel("Label").SetAriaDescribedby("a", "b")
+	if err := tag.SetDataJSON("config", map[string]interface{}{"a": "<b>", "n": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	const kCompare = `<div aria-describedby="a b" aria-expanded="false" aria-hidden="true" aria-label="Label" data-config="{&#34;a&#34;:&#34;\u003cb\u003e&#34;,&#34;n&#34;:1}" data-empty="" data-user-id="42" role="dialog"></div>`
+	if err := compare(t, tag, kCompare); err != nil {
+		t.Error(err)
+	}
+
+	if v := tag.Data("user-id"); v != "42" {
+		t.Errorf("unexpected data value %q", v)
+	}
+
+	// Invalid keys and names are ignored.
+	tag.SetAria("x onclick=alert(1) y", "v").SetAria("a-b", "v").SetAria("", "v")
+	tag.SetData("x onclick=alert(1) y", "1").SetData(`a"b`, "1").SetData("", "1")
+	if err := compare(t, tag, kCompare); err != nil {
+		t.Error(err)
+	}
+	if err := tag.SetDataJSON("a b", 1); err != ErrInvalidDataKey {
+		t.Errorf("expected ErrInvalidDataKey, got %v", err)
+	}
+
+	tag.RemoveData("config").RemoveData("empty").RemoveData("userId")
+	tag.SetAria("describedby", "").SetAriaHidden(false).SetAriaLabel("").SetAria("expanded", "").SetRole("")
+	if err := compare(t, tag, `<div></div>`); err != nil {
+		t.Error(err)
+	}
+
+	if err := tag.SetDataJSON("bad", func() {}); err == nil {
+		t.Errorf("expected error for unsupported JSON value")
+	}
+}
+
+func Test_Events(t *testing.T) {
+	tag := H.Div().SetOn(EventPointerdown, `start("a", 'b' && x < 1)`).SetOnclick("go()")
+	tag.SetOn(EventName(`x" onload="evil`), "ignored")
+	// Shorthand setters write the script verbatim.
+	tag.SetOnfocus("a &amp;&amp; b()")
+	const kCompare = `<div onclick="go()" onfocus="a &amp;&amp; b()" onpointerdown="start(&#34;a&#34;, &#39;b&#39; &amp;&amp; x &lt; 1)"></div>`
+	if err := compare(t, tag, kCompare); err != nil {
+		t.Error(err)
+	}
+
+	tag.SetOn(EventPointerdown, "").SetOnclick("").SetOnfocus("")
+	if err := compare(t, tag, `<div></div>`); err != nil {
+		t.Error(err)
+	}
+
+	img := H.Img("src", "alt")
+	img.SetOn(EventError, "fallback(this)")
+	if err := compare(t, img, `<img alt="alt" onerror="fallback(this)" src="src" />`); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_FormStructure(t *testing.T) {
+	const kCompare = `<form>
+  <fieldset disabled="" name="shipping">
+    <legend>
+      Shipping
+    </legend>
+    <select name="speed">
+      <option value="none"></option>
+      <optgroup label="Fast">
+        <option selected="" value="express"></option>
+      </optgroup>
+      <optgroup disabled="" label="Slow"></optgroup>
+    </select>
+  </fieldset>
+  <output for="a b" name="total"></output>
+  <progress max="100" value="0"></progress>
+  <progress></progress>
+  <meter high="0.8" low="0.2" max="1" min="0" optimum="0.5" value="0.6"></meter>
+</form>`
+
+	form := H.Form()
+	fieldset := form.Fieldset(&FieldsetOptions{Disabled: true, Name: "shipping"})
+	fieldset.Legend("Shipping")
+	sel := fieldset.Select(&SelectOptions{Name: "speed"})
+	sel.Option(&OptionOptions{Value: "none"})
+	express := sel.Optgroup("Fast").Option(&OptionOptions{Value: "express", Selected: true})
+	sel.Optgroup("Slow", &OptgroupOptions{Disabled: true})
+	form.Output(&OutputOptions{For: []string{"a", "b"}, Name: "total"})
+	form.Progress(&ProgressOptions{Max: 100, Value: Float(0)})
+	form.Progress()
+	form.Meter(&MeterOptions{
+		High:    Float(0.8),
+		Low:     Float(0.2),
+		Max:     Float(1),
+		Min:     Float(0),
+		Optimum: Float(0.5),
+		Value:   0.6,
+	})
+
+	if err := compareHtml(form, kCompare, true); err != nil {
+		t.Error(err)
+	}
+
+	if options := sel.OptionChildren(); len(options) != 2 || options[1] != express {
+		t.Errorf("unexpected option children %v", options)
+	}
+	if selected, err := sel.SelectedOption(); err != nil || selected != express {
+		t.Errorf("unexpected selected option %v, %v", selected, err)
+	}
+
+	express.SetSelected(false)
+	if _, err := sel.SelectedOption(); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	sel.RemoveChildren()
+	if options := sel.OptionChildren(); len(options) != 0 {
+		t.Errorf("expected no options after RemoveChildren, got %v", options)
+	}
+}
+
+func Test_GlobalAttributes(t *testing.T) {
+	tag := H.Div().SetAccesskey("s").SetAutofocus(true).SetContentEditable(ContentEditablePlaintextOnly)
+	tag.SetDir(DirRtl).SetDraggable(DraggableTrue).SetHidden(true).SetInert(true).SetLang("ar")
+	tag.SetSpellcheck(SpellcheckFalse).SetTabindex(0).SetTranslate(TranslateNo)
+
+	const kCompare = `<div accesskey="s" autofocus="" contenteditable="plaintext-only" dir="rtl" draggable="true" hidden="" inert="" lang="ar" spellcheck="false" tabindex="0" translate="no"></div>`
+	if err := compare(t, tag, kCompare); err != nil {
+		t.Error(err)
+	}
+
+	tag.SetAccesskey("").SetAutofocus(false).SetContentEditable("").SetDir("").SetDraggable("")
+	tag.SetHidden(false).SetInert(false).SetLang("").SetSpellcheck("").RemoveTabindex().SetTranslate("")
+	if err := compare(t, tag, `<div></div>`); err != nil {
+		t.Error(err)
+	}
+}
+
 func Test_Htmlgen(t *testing.T) {
 	const kCompare = `<!DOCTYPE html>
 <html>
@@ -403,6 +631,196 @@ func Test_Htmlgen(t *testing.T) {
 	}
 }
 
+func Test_Sectioning(t *testing.T) {
+	const kCompare = `<body>
+  <header class="top">
+    <nav id="menu"></nav>
+    <hgroup class="titles">
+      <h1></h1>
+    </hgroup>
+  </header>
+  <main id="content">
+    <search class="site" id="find"></search>
+    <section class="a b" id="s">
+      <article id="post">
+        <figure class="wide">
+          <figcaption id="cap">
+            caption
+          </figcaption>
+        </figure>
+      </article>
+      <aside class="note"></aside>
+    </section>
+  </main>
+</body>`
+
+	body := H.Body()
+	header := body.HeaderClasses("top")
+	header.NavId("menu")
+	header.HgroupClasses("titles").H1()
+	main := body.MainId("content")
+	main.SearchIdClasses("find", "site")
+	section := main.SectionIdClasses("s", "a", "b")
+	section.ArticleId("post").FigureClasses("wide").FigcaptionId("cap").T("caption")
+	section.AsideClasses("note")
+
+	if err := compareHtml(body, kCompare, true); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_RenderMode(t *testing.T) {
+	const kHtmlCompare = `<form>
+  <input checked name="a" type="checkbox">
+  <br>
+  <select multiple></select>
+</form>`
+	const kXhtmlCompare = `<form>
+  <input checked="" name="a" type="checkbox" />
+  <br />
+  <select multiple=""></select>
+</form>`
+
+	form := H.Form()
+	form.CheckedInputTypeNameValue(CheckedInputTypeCheckbox, "a", "").SetChecked(true)
+	form.Br()
+	form.Select(&SelectOptions{Multiple: true})
+
+	// Alternate modes to exercise the per-mode open tag cache.
+	tests := []struct {
+		mode     RenderMode
+		expected string
+	}{
+		{RenderModeHtml, kHtmlCompare},
+		{RenderModeXhtml, kXhtmlCompare},
+		{RenderModeXml, kXhtmlCompare},
+		{RenderModeHtml, kHtmlCompare},
+	}
+	for _, test := range tests {
+		buf := new(bytes.Buffer)
+		if _, err := RenderPretty(buf, form, &RenderOptions{Mode: test.mode}); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != test.expected {
+			t.Errorf("mode %d: expected:\n%s\ngot:\n%s", test.mode, test.expected, buf.String())
+		}
+	}
+
+	br := H.Br()
+	buf := new(bytes.Buffer)
+	if _, err := Render(buf, br, &RenderOptions{Mode: RenderModeHtml}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "<br>" {
+		t.Errorf("expected <br>, got %s", buf.String())
+	}
+	if err := compareHtml(br, "<br />", false); err != nil {
+		t.Error(err)
+	}
+
+	// Variables are substituted from the options.
+	p := H.P()
+	p.TV("$name")
+	buf.Reset()
+	if _, err := Render(buf, p, &RenderOptions{Env: Environment{"name": StringValue("a&b")}}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "<p>a&amp;b</p>" {
+		t.Errorf("expected <p>a&amp;b</p>, got %s", buf.String())
+	}
+}
+
+func Test_Scripts(t *testing.T) {
+	const kCompare = `<head>
+  <script async="" crossorigin="use-credentials" integrity="sha384-abc" nonce="r4nd" referrerpolicy="no-referrer" src="a.js"></script>
+  <script src="app.mjs" type="module"></script>
+  <script defer="" nomodule="" src="legacy.js"></script>
+  <script nonce="r4nd" type="importmap">
+    {"imports":{"app":"/js/app.mjs?a=1\u0026b=\u003c/script\u003e"},"scopes":{"/admin/":{"app":"/js/admin.mjs"}}}
+  </script>
+  <style nonce="r4nd"></style>
+</head>`
+
+	head := H.Head()
+	head.ScriptSrc("", "a.js", &ScriptOptions{
+		Async:          true,
+		Crossorigin:    CrossOriginUseCredentials,
+		Integrity:      "sha384-abc",
+		Nonce:          "r4nd",
+		Referrerpolicy: ReferrerPolicyNoReferrer,
+	})
+	head.ScriptSrc(MimeTypeJavascript, "app.mjs", &ScriptOptions{Module: true})
+	head.ScriptSrc("", "legacy.js", &ScriptOptions{Defer: true, Nomodule: true})
+	head.ScriptImportMap(&ImportMap{
+		Imports: map[string]string{"app": "/js/app.mjs?a=1&b=</script>"},
+		Scopes: map[string]map[string]string{
+			"/admin/": {"app": "/js/admin.mjs"},
+		},
+	}, &ScriptOptions{Nonce: "r4nd"})
+	head.Style(&StyleOptions{Nonce: "r4nd"})
+
+	if err := compareHtml(head, kCompare, true); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_ShadowRoot(t *testing.T) {
+	const kCompare = `<user-card>
+  <template shadowrootdelegatesfocus="" shadowrootmode="open">
+    <style></style>
+    <slot name="title"></slot>
+    <slot></slot>
+  </template>
+  <span slot="title">
+    Ada
+  </span>
+</user-card>`
+
+	card, err := H.Element("user-card")
+	if err != nil {
+		t.Fatal(err)
+	}
+	card.Span().SetAttribute("slot", "title").T("Ada")
+	shadow, err := card.AttachShadow(ShadowRootModeOpen, &TemplateOptions{DelegatesFocus: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	shadow.Style()
+	shadow.Slot("title")
+	if existing, err := card.AttachShadow(ShadowRootModeClosed); err != nil || existing != shadow {
+		t.Errorf("expected the existing shadow root, got %v", err)
+	}
+	shadow.Slot("")
+
+	if err := compareHtml(card, kCompare, true); err != nil {
+		t.Error(err)
+	}
+
+	if err := compareHtml(H.Template(), `<template></template>`, false); err != nil {
+		t.Error(err)
+	}
+
+	// Empty modes and elements that cannot host a shadow root are
+	// rejected without adding a template.
+	div := H.Div()
+	for ii := 0; ii < 2; ii++ {
+		if _, err := div.AttachShadow(""); err != ErrInvalidShadowRoot {
+			t.Errorf("expected ErrInvalidShadowRoot, got %v", err)
+		}
+	}
+	if err := compareHtml(div, `<div></div>`, false); err != nil {
+		t.Error(err)
+	}
+	for _, host := range []Tag{H.Br(), H.Img("a.png", ""), H.Ul(), H.Div().Svg().G()} {
+		if _, err := host.AttachShadow(ShadowRootModeOpen); err != ErrInvalidShadowRoot {
+			t.Errorf("expected ErrInvalidShadowRoot, got %v", err)
+		}
+	}
+	if _, err := div.AttachShadow(ShadowRootModeOpen); err != nil {
+		t.Error(err)
+	}
+}
+
 func Test_Simple(t *testing.T) {
 	const kCompare = `<!DOCTYPE html><html><head><link rel="search" /><link rel="stylesheet" /></head></html>`
 
@@ -416,6 +834,297 @@ func Test_Simple(t *testing.T) {
 	}
 }
 
+func Test_Input(t *testing.T) {
+	const kCompare = `<form>
+  <input max="10" maxlength="0" min="0" step="any" type="number" />
+  <input max="2014-12-31" min="2014-01-01" required="" step="7" type="date" />
+  <input accept="image/*" capture="environment" multiple="" type="file" />
+  <input autocomplete="shipping street-address" dirname="addr.dir" enterkeyhint="next" inputmode="text" minlength="3" name="addr" type="text" />
+  <input formaction="/save" formmethod="post" formnovalidate="" type="submit" />
+  <input type="email" />
+</form>`
+
+	start := time.Date(2014, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2014, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	form := H.Form()
+	form.Input(InputTypeNumber, &InputOptions{
+		Max:       InputNumber(10),
+		Maxlength: Int(0),
+		Min:       InputNumber(0),
+		Step:      InputStepAny,
+	})
+	form.Input(InputTypeDate, &InputOptions{
+		Max:      InputDate(end),
+		Min:      InputDate(start),
+		Required: true,
+		Step:     InputNumber(7),
+	})
+	form.Input(InputTypeFile, &InputOptions{
+		Accept:   "image/*",
+		Capture:  CaptureEnvironment,
+		Multiple: true,
+	})
+	form.Input(InputTypeText, &InputOptions{
+		Autocomplete: []AutocompleteToken{AutocompleteShipping, AutocompleteStreetAddress},
+		Dirname:      "addr.dir",
+		Enterkeyhint: EnterKeyHintNext,
+		Inputmode:    InputModeText,
+		Minlength:    Int(3),
+		Name:         "addr",
+	})
+	form.Input(InputTypeSubmit, &InputOptions{
+		Formaction:     "/save",
+		Formmethod:     FormMethodPost,
+		Formnovalidate: true,
+	})
+	email := form.Input(InputTypeEmail, &InputOptions{Placeholder: "you@example.com", Size: Int(20)})
+	email.ResetOptions()
+
+	if err := compareHtml(form, kCompare, true); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Interactive(t *testing.T) {
+	const kCompare = `<body>
+  <details open="">
+    <summary>
+      More &amp; less
+    </summary>
+    body
+  </details>
+  <dialog aria-modal="true" id="confirm">
+    <menu>
+      <li></li>
+    </menu>
+  </dialog>
+  <dialog open=""></dialog>
+</body>`
+
+	body := H.Body()
+	details := body.Details(false)
+	details.Summary("More & less")
+	details.T("body")
+	if details.Open() {
+		t.Error("details should be closed")
+	}
+	details.SetOpen(true)
+
+	// A modal dialog is shown by scripts with showModal().
+	dialog := body.Dialog(false, true)
+	dialog.SetId("confirm")
+	dialog.Menu().Li()
+	if dialog.Open() || !dialog.Modal() {
+		t.Error("dialog should be closed and modal")
+	}
+	shown := body.Dialog(true, false)
+	if !shown.Open() || shown.Modal() {
+		t.Error("dialog should be open and non-modal")
+	}
+
+	if err := compareHtml(body, kCompare, true); err != nil {
+		t.Error(err)
+	}
+
+	dialog.SetModal(false)
+	shown.SetOpen(false)
+	if dialog.Modal() || shown.Open() {
+		t.Error("dialogs should be closed and non-modal")
+	}
+}
+
+func Test_Element(t *testing.T) {
+	const kCompare = `<div>
+  <my-widget class="w" data-id="7">
+    <span>
+      x
+    </span>
+  </my-widget>
+  <keygen name="key" />
+</div>`
+	const kCopyCompare = `<my-widget class="w copy" data-id="7"></my-widget>`
+
+	div := H.Div()
+	widget, err := div.Element("my-widget")
+	if err != nil {
+		t.Fatal(err)
+	}
+	widget.AddClass("w").SetData("id", "7")
+	widget.Span().T("x")
+	keygen, err := div.VoidElement("keygen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keygen.SetAttribute("name", "key")
+
+	if err := compareHtml(div, kCompare, true); err != nil {
+		t.Error(err)
+	}
+
+	widgetCopy := widget.Copy()
+	widgetCopy.AddClass("copy")
+	if err := compareHtml(widgetCopy, kCopyCompare, true); err != nil {
+		t.Error(err)
+	}
+
+	for _, name := range []string{"", "My-widget", "1-a", "-a", "a b", "font-face", "a<b"} {
+		if _, err := H.Element(name); err != ErrInvalidElementName {
+			t.Errorf("Element(%q): expected ErrInvalidElementName, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"a>", "x-icon", "div", "BR"} {
+		if _, err := H.VoidElement(name); err != ErrInvalidElementName {
+			t.Errorf("VoidElement(%q): expected ErrInvalidElementName, got %v", name, err)
+		}
+	}
+}
+
+func Test_Embedded(t *testing.T) {
+	const kCompare = `<body>
+  <iframe allow="camera 'self' https://maps.example.com; fullscreen" allowfullscreen="" height="300" loading="lazy" referrerpolicy="no-referrer" sandbox="allow-scripts allow-forms" src="https://maps.example.com" title="Map" width="400"></iframe>
+  <iframe sandbox="" srcdoc="&lt;p&gt;a &amp;amp; &amp;#34;b&amp;#34;&lt;/p&gt;"></iframe>
+  <embed height="10" src="movie.swf" type="application/x-shockwave-flash" width="20" />
+  <object data="movie.swf" height="10" type="application/x-shockwave-flash" width="20">
+    <param name="quality" value="high" />
+  </object>
+</body>`
+
+	body := H.Body()
+	body.Iframe("https://maps.example.com", &IframeOptions{
+		Allow: AllowPolicy{}.Add("camera", AllowOriginSelf, "https://maps.example.com").
+			Add("fullscreen").Add("bad;feature").Add("geolocation;", "a;b"),
+		Allowfullscreen: true,
+		Height:          300,
+		Loading:         LoadingLazy,
+		Referrerpolicy:  ReferrerPolicyNoReferrer,
+		SandboxAllow:    []SandboxToken{SandboxAllowScripts, SandboxAllowForms},
+		Title:           "Map",
+		Width:           400,
+	})
+	srcdoc := H.P()
+	srcdoc.T(`a & "b"`)
+	body.Iframe("", &IframeOptions{Sandbox: true, Srcdoc: srcdoc})
+	body.Embed("movie.swf", "application/x-shockwave-flash", &EmbedOptions{Height: 10, Width: 20})
+	body.Object(&ObjectOptions{
+		Data:   "movie.swf",
+		Height: 10,
+		Type:   "application/x-shockwave-flash",
+		Width:  20,
+	}).Param("quality", "high")
+
+	if err := compareHtml(body, kCompare, true); err != nil {
+		t.Error(err)
+	}
+
+	// srcdoc is rendered at write time with the environment and mode.
+	doc := H.Div()
+	frame := H.Iframe("", &IframeOptions{Srcdoc: doc})
+	doc.TV("$name")
+	doc.Br()
+	buf := new(bytes.Buffer)
+	if _, err := Render(buf, frame, &RenderOptions{Env: Environment{"name": StringValue("x")}, Mode: RenderModeHtml}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := `<iframe srcdoc="&lt;div&gt;x&lt;br&gt;&lt;/div&gt;"></iframe>`; buf.String() != expected {
+		t.Errorf("expected %s, got %s", expected, buf.String())
+	}
+}
+
+func Test_Img(t *testing.T) {
+	const kCompare = `<p>
+  before
+  <img alt="alt" crossorigin="anonymous" decoding="async" fetchpriority="low" loading="lazy" referrerpolicy="no-referrer" sizes="(max-width: 600px) 480px, 800px" src="a.jpg" srcset="a.jpg 480w, b.jpg 800w" />
+  after
+  <img alt="invalid" src="c.jpg" />
+</p>`
+
+	p := H.P()
+	p.T("before").Img("a.jpg", "alt", &ImgOptions{
+		Crossorigin:    CrossOriginAnonymous,
+		Decoding:       DecodingAsync,
+		Fetchpriority:  FetchPriorityLow,
+		Loading:        LoadingLazy,
+		Referrerpolicy: ReferrerPolicyNoReferrer,
+		Sizes:          "(max-width: 600px) 480px, 800px",
+		Srcset:         Srcset{{URL: "a.jpg", Width: 480}, {URL: "b.jpg", Width: 800}},
+	}).T("after")
+	// Invalid srcsets are omitted.
+	invalid := Srcset{{URL: "c d.jpg"}}
+	if invalid.Validate() == nil {
+		t.Fatal("expected srcset to be invalid")
+	}
+	p.Img("c.jpg", "invalid", &ImgOptions{Srcset: invalid})
+
+	if err := compareHtml(p, kCompare, true); err != nil {
+		t.Error(err)
+	}
+	if err := compareHtml(H.Source(&SourceOptions{Srcset: invalid}), `<source />`, false); err != nil {
+		t.Error(err)
+	}
+	if err := compareHtml(H.Link(LinkRelPreload, &LinkOptions{Imagesrcset: invalid}), `<link rel="preload" />`, false); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Media(t *testing.T) {
+	const kCompare = `<body>
+  <video controls="" height="240" muted="" playsinline="" poster="poster.jpg" preload="metadata" width="320">
+    <source src="movie.webm" type="video/webm" />
+    <track default="" kind="captions" label="English" src="en.vtt" srclang="en" />
+  </video>
+  <audio autoplay="" loop="" src="song.ogg"></audio>
+  <picture>
+    <source media="(min-width: 800px)" srcset="large.avif" type="image/avif" />
+    <source sizes="50vw" srcset="small.jpg 400w" />
+    <img alt="alt" src="fallback.jpg" width="100" />
+  </picture>
+</body>`
+
+	body := H.Body()
+	video := body.Video(&VideoOptions{
+		Controls:    true,
+		Height:      240,
+		Muted:       true,
+		Playsinline: true,
+		Poster:      "poster.jpg",
+		Preload:     PreloadMetadata,
+		Width:       320,
+	})
+	video.Source(&SourceOptions{Src: "movie.webm", Type: "video/webm"})
+	video.Track("en.vtt", &TrackOptions{Default: true, Kind: TrackKindCaptions, Label: "English", Srclang: "en"})
+	body.Audio(&AudioOptions{Autoplay: true, Loop: true, Src: "song.ogg"})
+	body.Picture("fallback.jpg", "alt", []*SourceOptions{
+		{Media: "(min-width: 800px)", Srcset: Srcset{{URL: "large.avif"}}, Type: "image/avif"},
+		{Sizes: "50vw", Srcset: Srcset{{URL: "small.jpg", Width: 400}}},
+	}, &ImgOptions{Width: 100})
+
+	if err := compareHtml(body, kCompare, true); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Phrasing(t *testing.T) {
+	const kCompare = `<p>a <mark>b</mark><s>c</s>H<sub>2</sub>O e<sup>x</sup> <q cite="q.html">q</q><bdi>d</bdi><bdo dir="rtl">e</bdo>` +
+		`<data value="42">forty-two</data><ins datetime="2024-03-01T09:30:00Z">new</ins><del cite="why.html">old</del>` +
+		`long<wbr />word <ruby>漢<rt>kan</rt></ruby><time datetime="2024-03-01T09:30:00.250+09:00">March 1</time></p>`
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	when := time.Date(2024, time.March, 1, 9, 30, 0, 0, time.UTC)
+
+	p := H.P()
+	p.T("a ").Mark("b").S("c").T("H").Sub("2").T("O e").Sup("x").T(" ").
+		Q("q", "q.html").Bdi("d").Bdo(DirRtl, "e").
+		DataValue("42", "forty-two").Ins("new", &EditOptions{Datetime: when}).
+		Del("old", &EditOptions{Cite: "why.html"}).
+		T("long").Wbr().T("word ").Ruby("漢", "kan").
+		Time(time.Date(2024, time.March, 1, 9, 30, 0, 250123456, tokyo), "January 2")
+
+	if err := compareHtml(p, kCompare, false); err != nil {
+		t.Error(err)
+	}
+}
+
 func Test_Remove(t *testing.T) {
 	const kCompare = `<!DOCTYPE html><html><head></head><body><div class="foo2"></div></body></html>`
 	root := NewRoot()
@@ -433,6 +1142,115 @@ func Test_Remove(t *testing.T) {
 
 }
 
+func Test_Root(t *testing.T) {
+	root := NewRoot(&RootOptions{Dir: DirLtr, Lang: "en", Manifest: "app.appcache"})
+	root.Body()
+	if err := compareHtml(root, `<!DOCTYPE html>
+<html dir="ltr" lang="en" manifest="app.appcache">
+  <body></body>
+</html>`, true); err != nil {
+		t.Error(err)
+	}
+
+	root = NewRoot(&RootOptions{Doctype: DoctypeXhtml1Strict})
+	if err := compareHtml(root, DoctypeXhtml1Strict+"<html></html>", false); err != nil {
+		t.Error(err)
+	}
+
+	root = NewRoot(&RootOptions{Doctype: DoctypeXhtml1Strict, OmitDoctype: true})
+	root.Body()
+	if err := compareHtml(root, "<html>\n  <body></body>\n</html>", true); err != nil {
+		t.Error(err)
+	}
+
+	fragment := NewFragment()
+	fragment.DivId("cart").T("3 items")
+	fragment.P().T("updated")
+	if err := compareHtml(fragment, `<div id="cart">3 items</div><p>updated</p>`, false); err != nil {
+		t.Error(err)
+	}
+	if err := compareHtml(fragment, `<div id="cart">
+  3 items
+</div>
+<p>
+  updated
+</p>`, true); err != nil {
+		t.Error(err)
+	}
+
+	// Hidden children do not introduce blank lines, and nested fragments
+	// are indented.
+	fragment = NewFragment()
+	fragment.Hr()
+	fragment.Div().Hide(true)
+	fragment.Br()
+	fragment.Span().Hide(true)
+	if err := compareHtml(fragment, "<hr />\n<br />", true); err != nil {
+		t.Error(err)
+	}
+	div := H.Div()
+	div.AddChild(fragment)
+	if err := compareHtml(div, "<div>\n  <hr />\n  <br />\n</div>", true); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Styles(t *testing.T) {
+	tag := H.Div().SetStyle("color", "red").SetStyle("Margin", "0").SetStyle("--gap", "1px")
+	if err := compare(t, tag, `<div style="color: red; margin: 0; --gap: 1px"></div>`); err != nil {
+		t.Error(err)
+	}
+
+	// Updating a property keeps its position.
+	tag.SetStyle("color", "blue").RemoveStyle("margin")
+	if err := compare(t, tag, `<div style="color: blue; --gap: 1px"></div>`); err != nil {
+		t.Error(err)
+	}
+
+	// Values are escaped for the attribute, and values that could end the
+	// declaration are ignored.
+	tag.SetStyle("font-family", `"Open Sans", 'A;B', sans-serif`).SetStyle("background", `url("a.png")`)
+	for _, value := range []string{`red;" onclick="x`, "red; x: y", "}", `"a`, `"a\`, "a /* b", "\"a\nb\""} {
+		tag.SetStyle("color", value)
+	}
+	const kCompare = `<div style="color: blue; --gap: 1px; font-family: &#34;Open Sans&#34;, &#39;A;B&#39;, sans-serif; background: url(&#34;a.png&#34;)"></div>`
+	if err := compare(t, tag, kCompare); err != nil {
+		t.Error(err)
+	}
+	if styles := tag.Styles(); styles["font-family"] != `"Open Sans", 'A;B', sans-serif` {
+		t.Errorf("unexpected styles %v", styles)
+	}
+
+	// Round-tripping through Styles() does not escape values again.
+	tag.SetStyle("background", tag.Styles()["background"])
+	if err := compare(t, tag, kCompare); err != nil {
+		t.Error(err)
+	}
+	tag.RemoveStyle("font-family", "background")
+
+	// Invalid property names are ignored.
+	tag.SetStyle("x:y", "1").SetStyle("color", "")
+	if err := compare(t, tag, `<div style="--gap: 1px"></div>`); err != nil {
+		t.Error(err)
+	}
+
+	if styles := tag.Styles(); len(styles) != 1 || styles["--gap"] != "1px" {
+		t.Errorf("unexpected styles %v", styles)
+	}
+
+	// Custom properties are case-sensitive.
+	tag.SetStyle("--Main-Color", "red").SetStyle("--main-color", "blue")
+	if err := compare(t, tag, `<div style="--gap: 1px; --Main-Color: red; --main-color: blue"></div>`); err != nil {
+		t.Error(err)
+	}
+	tag.RemoveStyle("--Main-Color", "--main-color")
+
+	tag.RemoveStyle("--gap")
+	if err := compare(t, tag, `<div></div>`); err != nil {
+		t.Error(err)
+	}
+}
+
 func Test_TextTag(t *testing.T) {
 	const kCompare = `<!DOCTYPE html>
 <html>
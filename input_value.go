@@ -0,0 +1,80 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"fmt"
+	"time"
+)
+
+// A typed value for the min, max and step attributes of <input>.  Use the
+// type that matches the input's type, such as InputNumber for
+// InputTypeNumber and InputDate for InputTypeDate.
+type InputValue interface {
+	String() string
+}
+
+// A keyword value, such as InputStepAny.
+type InputKeyword string
+
+// Allows any step precision.
+const InputStepAny = InputKeyword("any")
+
+func (k InputKeyword) String() string {
+	return string(k)
+}
+
+// A number for number and range inputs, as well as the step for all inputs.
+type InputNumber float64
+
+func (n InputNumber) String() string {
+	return formatFloat(float64(n))
+}
+
+// A date (e.g., 2006-01-02) for date inputs.
+type InputDate time.Time
+
+func (d InputDate) String() string {
+	return time.Time(d).Format("2006-01-02")
+}
+
+// A local date and time (e.g., 2006-01-02T15:04) for datetime-local inputs.
+type InputDateTimeLocal time.Time
+
+func (d InputDateTimeLocal) String() string {
+	t := time.Time(d)
+	return t.Format("2006-01-02T") + formatInputTime(t)
+}
+
+// A month (e.g., 2006-01) for month inputs.
+type InputMonth time.Time
+
+func (m InputMonth) String() string {
+	return time.Time(m).Format("2006-01")
+}
+
+// A time of day (e.g., 15:04) for time inputs.
+type InputTime time.Time
+
+func (t InputTime) String() string {
+	return formatInputTime(time.Time(t))
+}
+
+// An ISO 8601 week (e.g., 2006-W01) for week inputs.
+type InputWeek time.Time
+
+func (w InputWeek) String() string {
+	year, week := time.Time(w).ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// Formats the time of day of t, omitting seconds and fractional seconds when
+// they are zero.
+func formatInputTime(t time.Time) string {
+	if t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format("15:04")
+	}
+	return t.Format("15:04:05.999")
+}
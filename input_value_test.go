@@ -0,0 +1,42 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"testing"
+	"time"
+)
+
+type InputValueTest struct {
+	value    InputValue
+	expected string
+}
+
+func TestInputValue(t *testing.T) {
+	noon := time.Date(2014, time.March, 5, 12, 30, 0, 0, time.UTC)
+	precise := time.Date(2014, time.March, 5, 12, 30, 15, 250000000, time.UTC)
+	newYear := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []InputValueTest{
+		{InputNumber(0), "0"},
+		{InputNumber(-1.5), "-1.5"},
+		{InputStepAny, "any"},
+		{InputDate(noon), "2014-03-05"},
+		{InputDateTimeLocal(noon), "2014-03-05T12:30"},
+		{InputDateTimeLocal(precise), "2014-03-05T12:30:15.25"},
+		{InputMonth(noon), "2014-03"},
+		{InputTime(noon), "12:30"},
+		{InputTime(precise), "12:30:15.25"},
+		{InputWeek(noon), "2014-W10"},
+		// January 1, 2016 belongs to the last ISO week of 2015.
+		{InputWeek(newYear), "2015-W53"},
+	}
+
+	for _, test := range tests {
+		if s := test.value.String(); s != test.expected {
+			t.Errorf("%#v => %q != %q expected", test.value, s, test.expected)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+const (
+	NamespaceMathml = "http://www.w3.org/1998/Math/MathML"
+)
+
+// Values for the display attribute of <math>.
+type MathDisplay string
+
+const (
+	MathDisplayBlock  = MathDisplay("block")
+	MathDisplayInline = MathDisplay("inline")
+)
+
+// Attributes for the root <math> element.
+type MathOptions struct {
+	// A text fallback for the equation.
+	Alttext string
+	// Inline by default.
+	Display MathDisplay
+}
+
+// A MathML element.  MathML elements are created with the methods of MathTag
+// rather than those of TagFactory, which create HTML elements.
+//
+// Layout elements, such as Mfrac() and Msup(), expect a fixed number of
+// children, which are added in order:
+//
+//	frac := math.Mfrac()
+//	frac.Mi("a")
+//	frac.Mn("2")
+type MathTag struct {
+	foreignTag
+}
+
+func newMathTag(name string) *MathTag {
+	return &MathTag{newForeignTag(name)}
+}
+
+// Adds a MathML child element named name to t and returns it.
+func (t *MathTag) addMathChild(name string) *MathTag {
+	newTag := newMathTag(name)
+	addChild(&t.baseTag, newTag)
+	return newTag
+}
+
+// Like addMathChild() but adds text, which is escaped, to the new element.
+func (t *MathTag) addMathToken(name, text string) *MathTag {
+	newTag := t.addMathChild(name)
+	newTag.T(text)
+	return newTag
+}
+
+func (t *MathTag) Copy() Tag {
+	newTag := t.baseTag.Copy().(*baseTag)
+	return &MathTag{foreignTag{*newTag}}
+}
+
+// Fraction with two children: the numerator and denominator.
+func (t *MathTag) Mfrac() *MathTag {
+	return t.addMathChild("mfrac")
+}
+
+// Identifier, such as a variable name.
+func (t *MathTag) Mi(text string) *MathTag {
+	return t.addMathToken("mi", text)
+}
+
+// Number.
+func (t *MathTag) Mn(text string) *MathTag {
+	return t.addMathToken("mn", text)
+}
+
+// Operator, fence or separator.
+func (t *MathTag) Mo(text string) *MathTag {
+	return t.addMathToken("mo", text)
+}
+
+// Overscript with two children: the base and the overscript.
+func (t *MathTag) Mover() *MathTag {
+	return t.addMathChild("mover")
+}
+
+// Radical with two children: the base and the index.
+func (t *MathTag) Mroot() *MathTag {
+	return t.addMathChild("mroot")
+}
+
+// Groups children horizontally.
+func (t *MathTag) Mrow() *MathTag {
+	return t.addMathChild("mrow")
+}
+
+// Square root of its children.
+func (t *MathTag) Msqrt() *MathTag {
+	return t.addMathChild("msqrt")
+}
+
+// Subscript with two children: the base and the subscript.
+func (t *MathTag) Msub() *MathTag {
+	return t.addMathChild("msub")
+}
+
+// Subscript and superscript with three children: the base, the subscript
+// and the superscript.
+func (t *MathTag) Msubsup() *MathTag {
+	return t.addMathChild("msubsup")
+}
+
+// Superscript with two children: the base and the superscript.
+func (t *MathTag) Msup() *MathTag {
+	return t.addMathChild("msup")
+}
+
+// Table of Mtr() rows.
+func (t *MathTag) Mtable() *MathTag {
+	return t.addMathChild("mtable")
+}
+
+// Table cell.
+func (t *MathTag) Mtd() *MathTag {
+	return t.addMathChild("mtd")
+}
+
+// Text that is not part of the notation.
+func (t *MathTag) Mtext(text string) *MathTag {
+	return t.addMathToken("mtext", text)
+}
+
+// Table row of Mtd() cells.
+func (t *MathTag) Mtr() *MathTag {
+	return t.addMathChild("mtr")
+}
+
+// Underscript with two children: the base and the underscript.
+func (t *MathTag) Munder() *MathTag {
+	return t.addMathChild("munder")
+}
+
+// Underscript and overscript with three children: the base, the
+// underscript and the overscript.
+func (t *MathTag) Munderover() *MathTag {
+	return t.addMathChild("munderover")
+}
+
+// Assigns the mathvariant attribute (e.g., "bold") and returns t.
+func (t *MathTag) SetMathvariant(variant string) *MathTag {
+	t.SetAttribute("mathvariant", variant)
+	return t
+}
@@ -0,0 +1,73 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"testing"
+)
+
+func TestMath(t *testing.T) {
+	const kCompare = `<p>
+  <math display="block" xmlns="http://www.w3.org/1998/Math/MathML">
+    <mrow>
+      <mi>
+        x
+      </mi>
+      <mo>
+        =
+      </mo>
+      <mfrac>
+        <mrow>
+          <mo>
+            -
+          </mo>
+          <mi>
+            b
+          </mi>
+        </mrow>
+        <mrow>
+          <mn>
+            2
+          </mn>
+          <msup>
+            <mi mathvariant="bold">
+              a
+            </mi>
+            <mn>
+              2
+            </mn>
+          </msup>
+        </mrow>
+      </mfrac>
+    </mrow>
+  </math>
+</p>`
+	const kWriteCompare = `<math xmlns="http://www.w3.org/1998/Math/MathML"><msqrt><mi>x</mi></msqrt><mo>&lt;</mo></math>`
+
+	p := H.P()
+	row := p.Math(&MathOptions{Display: MathDisplayBlock}).Mrow()
+	row.Mi("x")
+	row.Mo("=")
+	frac := row.Mfrac()
+	num := frac.Mrow()
+	num.Mo("-")
+	num.Mi("b")
+	den := frac.Mrow()
+	den.Mn("2")
+	sup := den.Msup()
+	sup.Mi("a").SetMathvariant("bold")
+	sup.Mn("2")
+
+	if err := compareHtml(p, kCompare, true); err != nil {
+		t.Error(err)
+	}
+
+	math := H.Math()
+	math.Msqrt().Mi("x")
+	math.Mo("<")
+	if err := compareHtml(math, kWriteCompare, false); err != nil {
+		t.Error(err)
+	}
+}
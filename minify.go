@@ -0,0 +1,116 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"bytes"
+	"strings"
+)
+
+// An element whose end tag is optional.  See
+// https://html.spec.whatwg.org/multipage/syntax.html#optional-tags
+type optionalEndTag struct {
+	// Sibling elements that implicitly end the element when they follow it.
+	followers map[string]bool
+	// True if the end tag may be omitted when the element is the last child
+	// of its parent.
+	lastChild bool
+}
+
+var (
+	optionalEndTags = map[string]optionalEndTag{
+		"dd":       {stringSet([]string{"dd", "dt"}), true},
+		"dt":       {stringSet([]string{"dd", "dt"}), false},
+		"li":       {stringSet([]string{"li"}), true},
+		"optgroup": {stringSet([]string{"optgroup"}), true},
+		"option":   {stringSet([]string{"optgroup", "option"}), true},
+		"p": {stringSet([]string{
+			"address", "article", "aside", "blockquote", "details",
+			"dialog", "div", "dl", "fieldset", "figcaption", "figure",
+			"footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header",
+			"hgroup", "hr", "main", "menu", "nav", "ol", "p", "pre",
+			"search", "section", "table", "ul",
+		}), true},
+		"td": {stringSet([]string{"td", "th"}), true},
+		"th": {stringSet([]string{"td", "th"}), true},
+		"tr": {stringSet([]string{"tr"}), true},
+	}
+
+	// Parents whose end tag does not implicitly end a <p>.  Custom elements
+	// are excluded as well.
+	pLastChildExcluded = stringSet([]string{
+		"a", "audio", "del", "ins", "map", "noscript", "video",
+	})
+
+	// Elements whose text keeps its whitespace in RenderModeHtmlMinified.
+	preformattedElements = stringSet([]string{
+		"pre", "script", "style", "textarea",
+	})
+)
+
+// Returns true if the end tag of the child at index i may be omitted in
+// RenderModeHtmlMinified.  This holds when the child is followed by a
+// sibling element that implicitly ends it or, for most elements, when the
+// child is the last of t.  Text in between keeps the end tag.
+func (t *baseTag) canOmitEndTag(i int) bool {
+	child, ok := t.children[i].(Tag)
+	if !ok || child.isHidden() {
+		return false
+	}
+	childStr := child.tagStr()
+	optional, ok := optionalEndTags[childStr]
+	if !ok {
+		return false
+	}
+
+	for _, next := range t.children[i+1:] {
+		if next.isHidden() {
+			continue
+		}
+		if _, ok := next.(*commentTag); ok {
+			// Comments are stripped.
+			continue
+		}
+		nextTag, ok := next.(Tag)
+		return ok && optional.followers[nextTag.tagStr()]
+	}
+
+	if !optional.lastChild {
+		return false
+	}
+	if childStr == "p" {
+		return t.tagType != kTagTypeCustom && !pLastChildExcluded[t.tagStr()]
+	}
+	return true
+}
+
+// Returns s with each run of HTML whitespace replaced by a single space.
+func collapseWhitespace(s string) string {
+	if !strings.ContainsAny(s, "\t\n\f\r") && !strings.Contains(s, "  ") {
+		return s
+	}
+
+	buf := new(bytes.Buffer)
+	space := false
+	for _, r := range s {
+		switch r {
+		case ' ', '\t', '\n', '\f', '\r':
+			if !space {
+				buf.WriteByte(' ')
+			}
+			space = true
+		default:
+			buf.WriteRune(r)
+			space = false
+		}
+	}
+	return buf.String()
+}
+
+// Returns true if value may be written as an attribute value without
+// quotes.
+func isUnquotedAttrValue(value string) bool {
+	return len(value) > 0 && !strings.ContainsAny(value, " \t\n\f\r\"'=<>`")
+}
@@ -0,0 +1,543 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// A node of the simplified DOM built by parseDom().
+type domNode struct {
+	name     string
+	attrs    map[string]string
+	children []*domNode
+	// Text of a text node, which has no name.
+	text string
+}
+
+// The tables below follow the tree construction rules of the HTML spec,
+// rather than the rules for omitting end tags, so that the tests check
+// minified output against an independent parser.  See
+// https://html.spec.whatwg.org/multipage/parsing.html#tree-construction
+var (
+	// Start tags that close a p element in button scope ("in body").
+	domClosesP = stringSet([]string{
+		"address", "article", "aside", "blockquote", "center", "details",
+		"dialog", "dir", "div", "dl", "fieldset", "figcaption", "figure",
+		"footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header",
+		"hgroup", "hr", "listing", "main", "menu", "nav", "ol", "p",
+		"plaintext", "pre", "search", "section", "summary", "table", "ul",
+		"xmp",
+	})
+	domHeadings  = stringSet([]string{"h1", "h2", "h3", "h4", "h5", "h6"})
+	domRowGroups = stringSet([]string{"tbody", "tfoot", "thead"})
+	// The special category, which bounds the search for an open li, dd or
+	// dt ("in body").
+	domSpecial = stringSet([]string{
+		"address", "applet", "area", "article", "aside", "base", "basefont",
+		"bgsound", "blockquote", "body", "br", "button", "caption", "center",
+		"col", "colgroup", "dd", "details", "dir", "div", "dl", "dt", "embed",
+		"fieldset", "figcaption", "figure", "footer", "form", "frame",
+		"frameset", "h1", "h2", "h3", "h4", "h5", "h6", "head", "header",
+		"hgroup", "hr", "html", "iframe", "img", "input", "keygen", "li",
+		"link", "listing", "main", "marquee", "menu", "meta", "nav",
+		"noembed", "noframes", "noscript", "object", "ol", "p", "param",
+		"plaintext", "pre", "script", "search", "section", "select",
+		"source", "style", "summary", "table", "tbody", "td", "template",
+		"textarea", "tfoot", "th", "thead", "title", "tr", "track", "ul",
+		"wbr", "xmp",
+	})
+
+	// Elements that bound "has an element in scope" and its variants.
+	domScope = stringSet([]string{
+		"applet", "caption", "html", "marquee", "object", "table", "td",
+		"template", "th",
+	})
+	domButtonScope   = unionSet(domScope, stringSet([]string{"button"}))
+	domListItemScope = unionSet(domScope, stringSet([]string{"ol", "ul"}))
+	domTableScope    = stringSet([]string{"html", "table", "template"})
+
+	domVoids = map[string]bool{"br": true, "hr": true, "img": true, "input": true, "meta": true}
+	domRaw   = map[string]bool{"pre": true, "script": true, "style": true, "textarea": true}
+)
+
+func unionSet(a, b map[string]bool) map[string]bool {
+	result := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		result[k] = true
+	}
+	for k := range b {
+		result[k] = true
+	}
+	return result
+}
+
+// Returns true if an element named name is open on stack within scope.
+func domInScope(stack []*domNode, name string, scope map[string]bool) bool {
+	for ii := len(stack) - 1; ii > 0; ii-- {
+		if stack[ii].name == name {
+			return true
+		}
+		if scope[stack[ii].name] {
+			return false
+		}
+	}
+	return false
+}
+
+// Pops stack up to and including the nearest element named one of names.
+func domPopUntil(stack []*domNode, names ...string) []*domNode {
+	for ii := len(stack) - 1; ii > 0; ii-- {
+		for _, name := range names {
+			if stack[ii].name == name {
+				return stack[:ii]
+			}
+		}
+	}
+	return stack
+}
+
+// Applies the tree construction rules for a start tag named name to stack,
+// the stack of open elements, and returns the new stack.  Elements that
+// the rules insert, such as an implied tbody, are added to the DOM.
+func domStartTag(stack []*domNode, name string) []*domNode {
+	top := func() string { return stack[len(stack)-1].name }
+	insert := func(name string) {
+		node := &domNode{name: name, attrs: make(map[string]string)}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, node)
+		stack = append(stack, node)
+	}
+
+	switch {
+	case name == "li" || name == "dd" || name == "dt":
+		// Close the nearest list item of the same kind, unless a special
+		// element other than address, div and p is open within it.
+		for ii := len(stack) - 1; ii > 0; ii-- {
+			open := stack[ii].name
+			if open == name || (name != "li" && (open == "dd" || open == "dt")) {
+				stack = stack[:ii]
+				break
+			}
+			if domSpecial[open] && open != "address" && open != "div" && open != "p" {
+				break
+			}
+		}
+		if domInScope(stack, "p", domButtonScope) {
+			stack = domPopUntil(stack, "p")
+		}
+
+	case domClosesP[name]:
+		if domInScope(stack, "p", domButtonScope) {
+			stack = domPopUntil(stack, "p")
+		}
+		if domHeadings[name] && domHeadings[top()] {
+			stack = stack[:len(stack)-1]
+		}
+
+	case name == "button":
+		if domInScope(stack, "button", domScope) {
+			stack = domPopUntil(stack, "button")
+		}
+
+	case name == "option" || name == "optgroup":
+		// "in select".
+		if top() == "option" {
+			stack = stack[:len(stack)-1]
+		}
+		if name == "optgroup" && top() == "optgroup" {
+			stack = stack[:len(stack)-1]
+		}
+
+	case name == "tr" || name == "td" || name == "th" || domRowGroups[name]:
+		// "in cell", "in row", "in table body" and "in table": close the
+		// open cell, row and row group as the start tag requires, then
+		// insert the implied tbody and tr.
+		if domInScope(stack, "td", domTableScope) || domInScope(stack, "th", domTableScope) {
+			stack = domPopUntil(stack, "td", "th")
+		}
+		if name != "td" && name != "th" && domInScope(stack, "tr", domTableScope) {
+			stack = domPopUntil(stack, "tr")
+		}
+		if domRowGroups[name] {
+			for group := range domRowGroups {
+				if domInScope(stack, group, domTableScope) {
+					stack = domPopUntil(stack, group)
+				}
+			}
+		}
+		if top() == "table" && !domRowGroups[name] {
+			insert("tbody")
+		}
+		if domRowGroups[top()] && (name == "td" || name == "th") {
+			insert("tr")
+		}
+	}
+	return stack
+}
+
+// Returns the scope within which an end tag named name must find its
+// element ("in body" and "in table").
+func domEndTagScope(name string) map[string]bool {
+	switch {
+	case name == "p":
+		return domButtonScope
+	case name == "li":
+		return domListItemScope
+	case name == "table" || name == "tr" || name == "td" || name == "th" || domRowGroups[name]:
+		return domTableScope
+	}
+	return domScope
+}
+
+// Parses the subset of HTML that is written by the tests into a DOM,
+// following domStartTag() for implied end tags and elements.  Comments and
+// doctypes are dropped, and text is kept verbatim.  End tags whose element
+// is not in scope are errors rather than ignored.
+func parseDom(s string) (*domNode, error) {
+	root := &domNode{}
+	stack := []*domNode{root}
+	top := func() *domNode { return stack[len(stack)-1] }
+
+	for len(s) > 0 {
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment: %s", s)
+			}
+			s = s[end+3:]
+
+		case strings.HasPrefix(s, "<!"):
+			s = s[strings.Index(s, ">")+1:]
+
+		case strings.HasPrefix(s, "</"):
+			end := strings.Index(s, ">")
+			name := s[2:end]
+			s = s[end+1:]
+			if !domInScope(stack, name, domEndTagScope(name)) {
+				return nil, fmt.Errorf("unmatched end tag: %s", name)
+			}
+			stack = domPopUntil(stack, name)
+
+		case strings.HasPrefix(s, "<"):
+			node, rest, err := parseDomTag(s)
+			if err != nil {
+				return nil, err
+			}
+			s = rest
+
+			stack = domStartTag(stack, node.name)
+			parent := top()
+			parent.children = append(parent.children, node)
+			if domRaw[node.name] {
+				end := strings.Index(s, "</"+node.name+">")
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated %s", node.name)
+				}
+				if end > 0 {
+					node.children = []*domNode{{text: s[:end]}}
+				}
+				s = s[end+len(node.name)+3:]
+			} else if !domVoids[node.name] {
+				stack = append(stack, node)
+			}
+
+		default:
+			end := strings.Index(s, "<")
+			if end < 0 {
+				end = len(s)
+			}
+			parent := top()
+			parent.children = append(parent.children, &domNode{text: s[:end]})
+			s = s[end:]
+		}
+	}
+	return root, nil
+}
+
+// Parses the start tag at the beginning of s.  Returns the node and the
+// remainder of s.
+func parseDomTag(s string) (*domNode, string, error) {
+	node := &domNode{attrs: make(map[string]string)}
+	end := strings.IndexAny(s, " />")
+	if end < 0 {
+		return nil, "", fmt.Errorf("unterminated tag: %s", s)
+	}
+	node.name = s[1:end]
+	s = s[end:]
+
+	for {
+		s = strings.TrimLeft(s, " /")
+		if len(s) == 0 {
+			return nil, "", fmt.Errorf("unterminated tag: %s", node.name)
+		}
+		if s[0] == '>' {
+			return node, s[1:], nil
+		}
+
+		end := strings.IndexAny(s, " =/>")
+		if end < 0 {
+			return nil, "", fmt.Errorf("unterminated attribute: %s", s)
+		}
+		key := s[:end]
+		s = s[end:]
+		if s[0] != '=' {
+			node.attrs[key] = ""
+			continue
+		}
+
+		s = s[1:]
+		if s[0] == '"' {
+			end := strings.Index(s[1:], `"`)
+			node.attrs[key] = s[1 : end+1]
+			s = s[end+2:]
+		} else {
+			end := strings.IndexAny(s, " >")
+			node.attrs[key] = s[:end]
+			s = s[end:]
+		}
+	}
+}
+
+// Writes the DOM in a canonical form to buf.
+func (n *domNode) writeTo(buf *bytes.Buffer, indent string) {
+	if len(n.name) == 0 {
+		fmt.Fprintf(buf, "%s%q\n", indent, n.text)
+		return
+	}
+
+	keys := make([]string, 0, len(n.attrs))
+	for k := range n.attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(buf, "%s%s", indent, n.name)
+	for _, k := range keys {
+		fmt.Fprintf(buf, " %s=%q", k, n.attrs[k])
+	}
+	buf.WriteString("\n")
+	for _, child := range n.children {
+		child.writeTo(buf, indent+"  ")
+	}
+}
+
+func (n *domNode) String() string {
+	buf := new(bytes.Buffer)
+	for _, child := range n.children {
+		child.writeTo(buf, "")
+	}
+	return buf.String()
+}
+
+func newMinifyTestRoot() Tag {
+	root := NewRoot()
+	body := root.Body()
+	body.Comment().T("navigation")
+
+	ul := body.Ul()
+	ul.SetClass("nav main")
+	ul.Li().A("/").T("Home")
+	item := ul.Li()
+	item.T("Nested  \n  list")
+	nested := item.Ul()
+	nested.Li().T("a")
+	nested.Li().P().T("b")
+	ul.Li().Hide(true)
+	ul.Li().T("Last")
+
+	body.P().T("Followed   by a div")
+	body.Div().P().T("last child of a div")
+	body.A("/x").P().T("last child of an anchor")
+	body.P().T("before search")
+	body.Element("search")
+	body.P().T("followed by text")
+	body.T(" trailing ")
+
+	table := body.Table()
+	for _, row := range [][]string{{"1", "2"}, {"3", "4"}} {
+		tr := table.Tr()
+		tr.Th().T(row[0])
+		tr.Td().T(row[1])
+	}
+	grid := body.Table()
+	grid.Thead().Tr().Th().T("h")
+	grid.Tbody().Tr().Td().T("d")
+	body.Button().P().T("in a button")
+
+	dl := body.Dl()
+	dl.Dt().T("term")
+	dl.Dd().T("definition")
+	dl.Dt().T("term 2")
+	dl.Dd().T("definition 2")
+
+	sel := body.Select()
+	sel.Option(&OptionOptions{Value: "a", Selected: true}).T("A")
+	sel.Option(&OptionOptions{Value: "b c", Disabled: true}).T("B")
+
+	body.Pre().T("  keep\n   this  ")
+	body.Script().TUnsafe("var a = 1;\n  var b = 2;")
+	body.InputTypeNameValue(InputTypeText, "q", "x").SetAttribute("placeholder", "")
+	return root
+}
+
+func TestRenderMinified(t *testing.T) {
+	// The expected DOM of newMinifyTestRoot(), with collapsed whitespace.
+	const kDom = `html
+  body
+    ul class="nav main"
+      li
+        a href="/"
+          "Home"
+      li
+        "Nested list"
+        ul
+          li
+            "a"
+          li
+            p
+              "b"
+      li
+        "Last"
+    p
+      "Followed by a div"
+    div
+      p
+        "last child of a div"
+    a href="/x"
+      p
+        "last child of an anchor"
+    p
+      "before search"
+    search
+    p
+      "followed by text"
+    " trailing "
+    table
+      tbody
+        tr
+          th
+            "1"
+          td
+            "2"
+        tr
+          th
+            "3"
+          td
+            "4"
+    table
+      thead
+        tr
+          th
+            "h"
+      tbody
+        tr
+          td
+            "d"
+    button
+      p
+        "in a button"
+    dl
+      dt
+        "term"
+      dd
+        "definition"
+      dt
+        "term 2"
+      dd
+        "definition 2"
+    select
+      option selected="" value="a"
+        "A"
+      option disabled="" value="b c"
+        "B"
+    pre
+      "  keep\n   this  "
+    script
+      "var a = 1;\n  var b = 2;"
+    input name="q" placeholder="" type="text" value="x"
+`
+
+	full := new(bytes.Buffer)
+	if _, err := Render(full, newMinifyTestRoot(), &RenderOptions{Mode: RenderModeHtml}); err != nil {
+		t.Fatal(err)
+	}
+	minified := new(bytes.Buffer)
+	if _, err := WriteMinified(minified, newMinifyTestRoot()); err != nil {
+		t.Fatal(err)
+	}
+	if minified.Len() >= full.Len() {
+		t.Errorf("expected smaller output:\n%s", minified.String())
+	}
+
+	dom, err := parseDom(minified.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dom.String() != kDom {
+		t.Errorf("DOM mismatch for:\n%s\nexpected:\n%s\ngot:\n%s", minified.String(), kDom, dom.String())
+	}
+
+	for _, s := range []string{
+		`<ul class="nav main"><li><a href=/>Home</a><li>Nested list<ul><li>a<li><p>b</ul><li>Last</ul>`,
+		`<p>Followed by a div<div><p>last child of a div</div>`,
+		`<a href=/x><p>last child of an anchor</p></a><p>before search<search></search>`,
+		`<p>followed by text</p> trailing `,
+		`<table><tr><th>1<td>2<tr><th>3<td>4</table>`,
+		`<table><thead><tr><th>h</thead><tbody><tr><td>d</tbody></table>`,
+		`<button><p>in a button</button>`,
+		`<dl><dt>term<dd>definition<dt>term 2<dd>definition 2</dl>`,
+		`>A<option `,
+		`value="b c"`,
+		`>B</select>`,
+		"<pre>  keep\n   this  </pre><script>var a = 1;\n  var b = 2;</script>",
+		` name=q `,
+		` placeholder></body>`,
+	} {
+		if !strings.Contains(minified.String(), s) {
+			t.Errorf("expected %q in:\n%s", s, minified.String())
+		}
+	}
+	if strings.Contains(minified.String(), "<!--") {
+		t.Errorf("expected comments to be stripped:\n%s", minified.String())
+	}
+}
+
+func TestRenderMinifiedUnsafe(t *testing.T) {
+	div := New().Div()
+	div.T("a   b ").TUnsafe("<pre>a   b</pre>")
+	div.TVUnsafe("<pre>$x   y</pre>")
+	div.P().T("c   d")
+
+	const kCompare = "<div>a   b <pre>a   b</pre><pre>x   y</pre><p>c d</div>"
+	buf := new(bytes.Buffer)
+	if _, err := WriteMinified(buf, div, Environment{"x": StringValue("x")}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != kCompare {
+		t.Errorf("expected %q, got %q", kCompare, buf.String())
+	}
+}
+
+func TestCollapseWhitespace(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"", ""},
+		{"a b", "a b"},
+		{"  a \t\n b\r\n", " a b "},
+		{"   ", "   "},
+	}
+	for _, test := range tests {
+		if result := collapseWhitespace(test.text); result != test.expected {
+			t.Errorf("%q => expected %q, got %q", test.text, test.expected, result)
+		}
+	}
+}
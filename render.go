@@ -0,0 +1,315 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	NamespaceXhtml = "http://www.w3.org/1999/xhtml"
+
+	kXmlDeclaration = `<?xml version="1.0" encoding="UTF-8"?>`
+)
+
+var (
+	// Matches a character or entity reference at the start of a string.
+	reCharRef = regexp.MustCompile(`^&(#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+
+	// Entities that XML predefines.
+	xmlEntities = map[string]bool{
+		"&amp;":  true,
+		"&apos;": true,
+		"&gt;":   true,
+		"&lt;":   true,
+		"&quot;": true,
+	}
+)
+
+// Serialization syntaxes for Render().
+type RenderMode int
+
+const (
+	// Void elements are closed as in XHTML (<br />), and attributes always
+	// have values (disabled="").  This is the default and matches Write().
+	RenderModeXhtml RenderMode = iota
+	// HTML5 syntax.  Void elements have no closing slash (<br>), and
+	// attributes with empty values, such as boolean attributes, are
+	// minimized (disabled).
+	RenderModeHtml
+	// XML syntax, which is suitable for XML pipelines and parses with
+	// encoding/xml.  Like RenderModeXhtml, void elements are self-closing
+	// and attributes are never minimized.  In addition:
+	//   - <html> is preceded by an XML declaration and has an xmlns.
+	//   - HTML entity references in text and attribute values become
+	//     character references, and stray '&' and invalid characters are
+	//     escaped or removed.
+	//   - Script and style text is written as a CDATA section.
+	//   - "--" is broken up in comments.
+	RenderModeXml
+	// HTML5 syntax for the smallest output, as for production.  In addition
+	// to the minimization of RenderModeHtml:
+	//   - Optional end tags, such as </li> and </p>, are omitted where the
+	//     HTML spec allows.
+	//   - Attribute values are unquoted when safe.
+	//   - Runs of whitespace in text are collapsed to a single space,
+	//     except within <pre>, <textarea>, <script> and <style> and in
+	//     text tags that hold unsafe text, such as from TUnsafe().
+	//   - Comments are stripped.
+	// The mode is intended for Render() rather than RenderPretty().
+	RenderModeHtmlMinified
+)
+
+// Returns true if m uses HTML5 syntax.
+func (m RenderMode) isHtml() bool {
+	return m == RenderModeHtml || m == RenderModeHtmlMinified
+}
+
+// Formatting for RenderPretty().
+type PrettyOptions struct {
+	// The indentation for each level, such as "\t".  Two spaces are used
+	// if empty.
+	Indent string
+	// Elements that are written on a single line, together with adjacent
+	// text and inline elements.  An element whose children are all
+	// inline is written on a single line as well.
+	InlineElements []string
+	// The maximum width of a line holding an open tag, beyond which each
+	// attribute is written on its own line.  Tabs count as one column.
+	// Attributes are never wrapped if MaxLineWidth is 0.  Elements written
+	// on a single line, such as inline elements, never have their
+	// attributes wrapped either.
+	MaxLineWidth int
+	// Elements, such as "pre", whose contents are written verbatim on
+	// the line of the open tag, so that whitespace is preserved.
+	PreserveElements []string
+}
+
+// Returns PrettyOptions that keep phrasing content inline and preserve the
+// contents of <pre>, <textarea>, <script> and <style>.
+func DefaultPrettyOptions() *PrettyOptions {
+	return &PrettyOptions{
+		Indent: kIndentDefault,
+		InlineElements: []string{
+			"a", "abbr", "b", "bdi", "bdo", "br", "button", "cite", "code",
+			"data", "del", "dfn", "em", "i", "img", "input", "ins", "kbd",
+			"label", "mark", "meter", "output", "progress", "q", "ruby", "s",
+			"samp", "select", "small", "span", "strong", "sub", "sup",
+			"time", "u", "var", "wbr",
+		},
+		PreserveElements: []string{"pre", "script", "style", "textarea"},
+	}
+}
+
+// Options for Render() and RenderPretty().
+type RenderOptions struct {
+	// The environment for variable substitution in TextTagVars.
+	Env  Environment
+	Mode RenderMode
+	// Formatting for RenderPretty().  If nil, the format of WritePretty()
+	// is used, in which every child is on its own line.
+	Pretty *PrettyOptions
+}
+
+// State that is threaded through the rendering of a tree.
+type renderContext struct {
+	env  Environment
+	mode RenderMode
+
+	// Pretty printing state.
+	indent       string
+	inline       map[string]bool
+	preserve     map[string]bool
+	maxLineWidth int
+	// True if open tags are written with sorted attributes rather than
+	// from the cache, for deterministic pretty output.
+	sortAttrs bool
+
+	// True while writing the children of a CDATA section.
+	inCdata bool
+	// True while writing a comment that is buffered for RenderModeXml.
+	inComment bool
+	// The depth of elements whose text keeps its whitespace in
+	// RenderModeHtmlMinified.
+	preformatted int
+	// Custom attributes that override those of the next element written,
+	// such as hx-swap-oob for RenderHtmxFragments().
+	overrideAttrs map[string]string
+	// True if the element being written may omit its end tag.  It is set by
+	// the parent for each child in RenderModeHtmlMinified.
+	omitEndTag bool
+}
+
+// Returns true if text() may modify text.
+func (c *renderContext) convertsText() bool {
+	return c.inCdata || c.mode == RenderModeXml ||
+		(c.mode == RenderModeHtmlMinified && c.preformatted == 0)
+}
+
+// Converts rendered text, which is already escaped for HTML, for output.
+func (c *renderContext) text(s string) string {
+	if c.mode == RenderModeHtmlMinified && c.preformatted == 0 && !c.inCdata {
+		return collapseWhitespace(s)
+	}
+	return c.unsafeText(s)
+}
+
+// Converts unsafe text, which is opaque markup, for output.  Unlike text(),
+// whitespace is never collapsed.
+func (c *renderContext) unsafeText(s string) string {
+	if c.inCdata {
+		// Split any "]]>" across two sections.
+		return strings.Replace(s, "]]>", "]]]]><![CDATA[>", -1)
+	}
+	if c.mode == RenderModeXml {
+		return xmlText(s)
+	}
+	return s
+}
+
+// options may be nil.
+func newRenderContext(options *RenderOptions) *renderContext {
+	ctx := &renderContext{indent: kIndentDefault}
+	if options == nil {
+		return ctx
+	}
+
+	ctx.env = options.Env
+	ctx.mode = options.Mode
+	if pretty := options.Pretty; pretty != nil {
+		if len(pretty.Indent) > 0 {
+			ctx.indent = pretty.Indent
+		}
+		ctx.inline = stringSet(pretty.InlineElements)
+		ctx.preserve = stringSet(pretty.PreserveElements)
+		ctx.maxLineWidth = pretty.MaxLineWidth
+	}
+	return ctx
+}
+
+// Returns true if child should be written on a line with adjacent inline
+// content when pretty printing.  Text is inline unless ctx uses the format
+// of WritePretty().
+func (c *renderContext) isInline(child tagWriter) bool {
+	switch child := child.(type) {
+	case *TextTag, *TextTagVar:
+		return c.inline != nil
+	case Tag:
+		return c.inline[child.tagStr()]
+	}
+	return false
+}
+
+// Returns true if the element named tagStr should be written compactly,
+// without indentation, when pretty printing.
+func (c *renderContext) isCompact(tagStr string) bool {
+	return c.inline[tagStr] || c.preserve[tagStr]
+}
+
+// Returns options holding the first of env, if any.
+func envOptions(env []Environment) *RenderOptions {
+	if len(env) == 0 {
+		return nil
+	}
+	return &RenderOptions{Env: env[0]}
+}
+
+// Returns the end of a void element's tag for mode.
+func voidTagEnd(mode RenderMode) string {
+	if mode.isHtml() {
+		return ">"
+	}
+	return " />"
+}
+
+// Writes root to writer according to options, which may be nil.
+func Render(writer io.Writer, root Tag, options *RenderOptions) (int, error) {
+	return root.write(writer, newRenderContext(options))
+}
+
+// Like Render() but with indentation and sorted attributes.
+func RenderPretty(writer io.Writer, root Tag, options *RenderOptions) (int, error) {
+	ctx := newRenderContext(options)
+	ctx.sortAttrs = true
+	return root.writePretty(writer, 0, ctx)
+}
+
+// Returns a set holding values.
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// Returns true if r may appear in an XML document.
+func isXmlChar(r rune) bool {
+	switch {
+	case r == '\t' || r == '\n' || r == '\r':
+		return true
+	case r < 0x20:
+		return false
+	case r >= 0xd800 && r <= 0xdfff:
+		return false
+	case r == 0xfffe || r == 0xffff:
+		return false
+	}
+	return true
+}
+
+// Escapes an attribute value for XML.  Unlike text, attribute values are
+// never markup.
+func xmlAttrValue(s string) string {
+	s = xmlText(s)
+	s = strings.Replace(s, "<", "&lt;", -1)
+	return strings.Replace(s, `"`, "&quot;", -1)
+}
+
+// Converts HTML text, which may include markup from TUnsafe(), to XML.
+// References to entities that XML lacks (e.g., &nbsp;) become character
+// references, '&' that does not begin a reference is escaped, and
+// characters that XML prohibits are removed.
+func xmlText(s string) string {
+	var result strings.Builder
+	for len(s) > 0 {
+		if s[0] == '&' {
+			ref := reCharRef.FindString(s)
+			switch {
+			case len(ref) == 0:
+				result.WriteString("&amp;")
+				s = s[1:]
+				continue
+			case ref[1] == '#' || xmlEntities[ref]:
+				result.WriteString(ref)
+			default:
+				unescaped := html.UnescapeString(ref)
+				if unescaped == ref {
+					// Unknown entity.
+					result.WriteString("&amp;")
+					s = s[1:]
+					continue
+				}
+				for _, r := range unescaped {
+					result.WriteString("&#" + strconv.Itoa(int(r)) + ";")
+				}
+			}
+			s = s[len(ref):]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s)
+		if isXmlChar(r) && !(r == utf8.RuneError && size == 1) {
+			result.WriteString(s[:size])
+		}
+		s = s[size:]
+	}
+	return result.String()
+}
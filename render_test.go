@@ -0,0 +1,166 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Returns an error if s is not well-formed XML.
+func parseXml(s string) error {
+	decoder := xml.NewDecoder(strings.NewReader(s))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func newXmlTestRoot() Tag {
+	root := NewRoot()
+	head := root.Head()
+	head.Script().TUnsafe("if (a < b && c) { x = ']]>'; }")
+	head.Style().TUnsafe("p > a { color: red; }")
+	body := root.Body()
+	body.Comment().T("a--b-")
+	p := body.P()
+	p.A("/search?q=1&page=2").T(`"Tom" & Jerry`)
+	p.TUnsafe("&nbsp;&copy; &bogus; & \x01<b>bold</b>")
+	p.TV("$name")
+	body.Br()
+	body.CheckedInputTypeNameValue(CheckedInputTypeCheckbox, "c", "").SetChecked(true)
+	return root
+}
+
+func TestRenderXml(t *testing.T) {
+	const kCompare = `<?xml version="1.0" encoding="UTF-8"?><!DOCTYPE html>` +
+		`<html xmlns="http://www.w3.org/1999/xhtml"><head>` +
+		`<script><![CDATA[if (a < b && c) { x = ']]]]><![CDATA[>'; }]]></script>` +
+		`<style><![CDATA[p > a { color: red; }]]></style></head>` +
+		`<body><!-- a- -b- --><p><a href="/search?q=1&amp;page=2">&#34;Tom&#34; &amp; Jerry</a>` +
+		`&#160;&#169; &amp;bogus; &amp; <b>bold</b>&lt;&amp;&gt;</p><br />`
+
+	env := Environment{"name": StringValue("<&>")}
+	buf := new(bytes.Buffer)
+	if _, err := Render(buf, newXmlTestRoot(), &RenderOptions{Env: env, Mode: RenderModeXml}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), kCompare) {
+		t.Errorf("expected prefix:\n%s\ngot:\n%s", kCompare, buf.String())
+	}
+	if err := parseXml(buf.String()); err != nil {
+		t.Errorf("%v:\n%s", err, buf.String())
+	}
+
+	buf.Reset()
+	if _, err := RenderPretty(buf, newXmlTestRoot(), &RenderOptions{Env: env, Mode: RenderModeXml}); err != nil {
+		t.Fatal(err)
+	}
+	if err := parseXml(buf.String()); err != nil {
+		t.Errorf("%v:\n%s", err, buf.String())
+	}
+
+	// The default mode is unaffected.
+	buf.Reset()
+	if _, err := Write(buf, newXmlTestRoot(), env); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "<!DOCTYPE html><html><head><script>if (a < b") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestXmlText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"", ""},
+		{"a &amp; b &#39; &#x27; &lt;", "a &amp; b &#39; &#x27; &lt;"},
+		{"&nbsp;&hellip;", "&#160;&#8230;"},
+		{"& &; &x &unknown;", "&amp; &amp;; &amp;x &amp;unknown;"},
+		{"a\x00b\x1fc\td\ufffe", "abc\td"},
+		{"bad \xff utf-8", "bad  utf-8"},
+	}
+	for _, test := range tests {
+		if result := xmlText(test.text); result != test.expected {
+			t.Errorf("%q => expected %q, got %q", test.text, test.expected, result)
+		}
+	}
+}
+
+func TestRenderPrettyOptions(t *testing.T) {
+	const kCompare = "<div>\n" +
+		"\t<p>Hello, <b>world</b> and <a href=\"/x\">more</a>.</p>\n" +
+		"\t<pre>  keep\n    this</pre>\n" +
+		"\t<ul>\n" +
+		"\t\t<li>one</li>\n" +
+		"\t</ul>\n" +
+		"\t<textarea cols=\"10\" rows=\"2\">  a\nb</textarea>\n" +
+		"\t<form\n" +
+		"\t\taction=\"/search\"\n" +
+		"\t\tdata-long-attribute=\"value\"\n" +
+		"\t\tmethod=\"get\"></form>\n" +
+		"\ttext <span>inline</span>\n" +
+		"\t<div></div>\n" +
+		"</div>"
+
+	div := H.Div()
+	p := div.P()
+	p.T("Hello, ")
+	p.B().T("world")
+	p.T(" and ")
+	p.A("/x").T("more")
+	p.T(".")
+	div.Pre().T("  keep\n    this")
+	ul := div.Ul()
+	ul.Li().T("one")
+	ul.Li().Hide(true)
+	div.Textarea(2, 10).T("  a\nb")
+	div.Form(&FormOptions{Action: "/search", Method: FormMethodGet}).SetAttribute("data-long-attribute", "value")
+	div.T("text ")
+	div.Span().T("inline")
+	div.Div().Hide(true)
+	div.Div().T("")
+
+	options := DefaultPrettyOptions()
+	options.Indent = "\t"
+	options.MaxLineWidth = 40
+
+	buf := new(bytes.Buffer)
+	if _, err := RenderPretty(buf, div, &RenderOptions{Pretty: options}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != kCompare {
+		t.Errorf("expected:\n%s\ngot:\n%s", kCompare, buf.String())
+	}
+}
+
+func TestRenderPrettyMaxLineWidthInline(t *testing.T) {
+	const kCompare = `<div>
+	<p><a href="/a/long/path" title="A long title">link</a></p>
+</div>`
+	div := New().Div()
+	p := div.P()
+	p.A("/a/long/path").SetAttribute("title", "A long title").T("link")
+
+	options := DefaultPrettyOptions()
+	options.Indent = "\t"
+	options.MaxLineWidth = 20
+
+	buf := new(bytes.Buffer)
+	if _, err := RenderPretty(buf, div, &RenderOptions{Pretty: options}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != kCompare {
+		t.Errorf("expected:\n%s\ngot:\n%s", kCompare, buf.String())
+	}
+}
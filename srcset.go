@@ -0,0 +1,104 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidSrcset = errors.New("invalid srcset")
+
+// A single image candidate of a srcset attribute.  At most one of Width and
+// Density may be set.  If neither is set, the candidate has an implicit
+// density of 1x.
+type SrcsetCandidate struct {
+	URL string
+	// Width descriptor in pixels (e.g., 480w).  Only applied if > 0.
+	Width int
+	// Pixel density descriptor (e.g., 2x).  Only applied if > 0.
+	Density float64
+}
+
+// The image candidates of a srcset attribute.  Width descriptors require a
+// sizes attribute on the element.
+type Srcset []SrcsetCandidate
+
+// Returns the descriptor for c, or the empty string if it has none.
+func (c *SrcsetCandidate) descriptor() string {
+	if c.Width > 0 {
+		return strconv.Itoa(c.Width) + "w"
+	}
+	if c.Density > 0 {
+		return strconv.FormatFloat(c.Density, 'g', -1, 64) + "x"
+	}
+	return ""
+}
+
+// Serializes s as a srcset attribute value.  s should be validated first.
+func (s Srcset) String() string {
+	candidates := make([]string, len(s))
+	for ii, c := range s {
+		if d := c.descriptor(); len(d) > 0 {
+			candidates[ii] = c.URL + " " + d
+		} else {
+			candidates[ii] = c.URL
+		}
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// Returns an error wrapping ErrInvalidSrcset if s cannot be serialized
+// unambiguously.  Each URL must be non-empty and free of whitespace and
+// leading or trailing commas, width and density descriptors must not be
+// mixed, and no two candidates may share a descriptor.
+func (s Srcset) Validate() error {
+	hasWidth := false
+	hasDensity := false
+	seen := make(map[string]bool, len(s))
+
+	for ii, c := range s {
+		if len(c.URL) == 0 {
+			return fmt.Errorf("%w: candidate %d has an empty URL", ErrInvalidSrcset, ii)
+		}
+		if strings.IndexFunc(c.URL, unicode.IsSpace) != -1 {
+			return fmt.Errorf("%w: candidate %d URL contains whitespace", ErrInvalidSrcset, ii)
+		}
+		if strings.HasPrefix(c.URL, ",") || strings.HasSuffix(c.URL, ",") {
+			return fmt.Errorf("%w: candidate %d URL starts or ends with a comma", ErrInvalidSrcset, ii)
+		}
+
+		if c.Width < 0 || c.Density < 0 {
+			return fmt.Errorf("%w: candidate %d has a negative descriptor", ErrInvalidSrcset, ii)
+		}
+		if c.Width > 0 && c.Density > 0 {
+			return fmt.Errorf("%w: candidate %d has both width and density", ErrInvalidSrcset, ii)
+		}
+
+		descriptor := c.descriptor()
+		if c.Width > 0 {
+			hasWidth = true
+		} else {
+			hasDensity = true
+			if len(descriptor) == 0 {
+				// The implicit descriptor.
+				descriptor = "1x"
+			}
+		}
+		if hasWidth && hasDensity {
+			return fmt.Errorf("%w: width and density descriptors are mixed", ErrInvalidSrcset)
+		}
+
+		if seen[descriptor] {
+			return fmt.Errorf("%w: duplicate descriptor %s", ErrInvalidSrcset, descriptor)
+		}
+		seen[descriptor] = true
+	}
+
+	return nil
+}
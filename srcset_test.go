@@ -0,0 +1,48 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"errors"
+	"testing"
+)
+
+type SrcsetTest struct {
+	srcset   Srcset
+	expected string
+	valid    bool
+}
+
+func TestSrcset(t *testing.T) {
+	tests := []SrcsetTest{
+		{Srcset{}, "", true},
+		{Srcset{{URL: "a.jpg"}}, "a.jpg", true},
+		{Srcset{{URL: "a.jpg"}, {URL: "b.jpg", Density: 2}}, "a.jpg, b.jpg 2x", true},
+		{Srcset{{URL: "a.jpg", Density: 1.5}, {URL: "b.jpg", Density: 2}}, "a.jpg 1.5x, b.jpg 2x", true},
+		{Srcset{{URL: "a.jpg", Width: 480}, {URL: "b.jpg", Width: 800}}, "a.jpg 480w, b.jpg 800w", true},
+		{Srcset{{URL: ""}}, "", false},
+		{Srcset{{URL: "a b.jpg"}}, "", false},
+		{Srcset{{URL: "a.jpg,"}}, "", false},
+		{Srcset{{URL: "a.jpg", Width: -1}}, "", false},
+		{Srcset{{URL: "a.jpg", Width: 480, Density: 2}}, "", false},
+		{Srcset{{URL: "a.jpg", Width: 480}, {URL: "b.jpg", Density: 2}}, "", false},
+		{Srcset{{URL: "a.jpg", Width: 480}, {URL: "b.jpg"}}, "", false},
+		{Srcset{{URL: "a.jpg"}, {URL: "b.jpg", Density: 1}}, "", false},
+		{Srcset{{URL: "a.jpg", Width: 480}, {URL: "b.jpg", Width: 480}}, "", false},
+	}
+
+	for _, test := range tests {
+		err := test.srcset.Validate()
+		if test.valid {
+			if err != nil {
+				t.Errorf("%v => unexpected error %v", test.srcset, err)
+			} else if s := test.srcset.String(); s != test.expected {
+				t.Errorf("%v => %q != %q expected", test.srcset, s, test.expected)
+			}
+		} else if !errors.Is(err, ErrInvalidSrcset) {
+			t.Errorf("%v => expected ErrInvalidSrcset, got %v", test.srcset, err)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"strings"
+)
+
+const (
+	NamespaceSvg   = "http://www.w3.org/2000/svg"
+	NamespaceXlink = "http://www.w3.org/1999/xlink"
+)
+
+// A point in user units.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Formats the points for the points attribute of <polyline> and <polygon>.
+func formatPoints(points []Point) string {
+	result := make([]string, len(points))
+	for i, p := range points {
+		result[i] = formatFloat(p.X) + "," + formatFloat(p.Y)
+	}
+	return strings.Join(result, " ")
+}
+
+// Builds the d attribute of <path>.  Each method appends a command with
+// absolute coordinates and returns p for chaining:
+//
+//	d := new(PathData).MoveTo(0, 0).LineTo(10, 0).LineTo(10, 10).Close()
+//	svg.Path(d.String())
+type PathData struct {
+	commands []string
+}
+
+func (p *PathData) add(command string, args ...float64) *PathData {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, command)
+	for _, arg := range args {
+		parts = append(parts, formatFloat(arg))
+	}
+	p.commands = append(p.commands, strings.Join(parts, " "))
+	return p
+}
+
+// Draws an elliptical arc to (x, y).
+func (p *PathData) ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) *PathData {
+	return p.add("A", rx, ry, rotation, boolToFloat(largeArc), boolToFloat(sweep), x, y)
+}
+
+// Closes the current subpath.
+func (p *PathData) Close() *PathData {
+	return p.add("Z")
+}
+
+// Draws a cubic Bézier curve to (x, y) with control points (x1, y1) and
+// (x2, y2).
+func (p *PathData) CubicTo(x1, y1, x2, y2, x, y float64) *PathData {
+	return p.add("C", x1, y1, x2, y2, x, y)
+}
+
+func (p *PathData) LineTo(x, y float64) *PathData {
+	return p.add("L", x, y)
+}
+
+func (p *PathData) MoveTo(x, y float64) *PathData {
+	return p.add("M", x, y)
+}
+
+// Draws a quadratic Bézier curve to (x, y) with control point (x1, y1).
+func (p *PathData) QuadTo(x1, y1, x, y float64) *PathData {
+	return p.add("Q", x1, y1, x, y)
+}
+
+func (p *PathData) String() string {
+	return strings.Join(p.commands, " ")
+}
+
+// Attributes for the root <svg> element.
+type SvgOptions struct {
+	// Height in user units (non-zero to set).
+	Height              float64
+	PreserveAspectRatio string
+	ViewBox             *ViewBox
+	// Width in user units (non-zero to set).
+	Width float64
+}
+
+// An SVG element.  SVG elements are created with the methods of SvgTag
+// rather than those of TagFactory, which create HTML elements.
+type SvgTag struct {
+	foreignTag
+}
+
+func newSvgTag(name string) *SvgTag {
+	return &SvgTag{newForeignTag(name)}
+}
+
+// Adds an SVG child element named name to t and returns it.
+func (t *SvgTag) addSvgChild(name string) *SvgTag {
+	newTag := newSvgTag(name)
+	addChild(&t.baseTag, newTag)
+	return newTag
+}
+
+// Assigns attribute key, whose case is preserved, and returns t.
+func (t *SvgTag) setSvgAttr(key, value string) *SvgTag {
+	t.SetAttribute(key, value)
+	return t
+}
+
+func (t *SvgTag) Circle(cx, cy, r float64) *SvgTag {
+	return t.addSvgChild("circle").
+		setSvgAttr("cx", formatFloat(cx)).
+		setSvgAttr("cy", formatFloat(cy)).
+		setSvgAttr("r", formatFloat(r))
+}
+
+func (t *SvgTag) Copy() Tag {
+	newTag := t.baseTag.Copy().(*baseTag)
+	return &SvgTag{foreignTag{*newTag}}
+}
+
+func (t *SvgTag) Defs() *SvgTag {
+	return t.addSvgChild("defs")
+}
+
+func (t *SvgTag) Ellipse(cx, cy, rx, ry float64) *SvgTag {
+	return t.addSvgChild("ellipse").
+		setSvgAttr("cx", formatFloat(cx)).
+		setSvgAttr("cy", formatFloat(cy)).
+		setSvgAttr("rx", formatFloat(rx)).
+		setSvgAttr("ry", formatFloat(ry))
+}
+
+func (t *SvgTag) G() *SvgTag {
+	return t.addSvgChild("g")
+}
+
+func (t *SvgTag) Line(x1, y1, x2, y2 float64) *SvgTag {
+	return t.addSvgChild("line").
+		setSvgAttr("x1", formatFloat(x1)).
+		setSvgAttr("y1", formatFloat(y1)).
+		setSvgAttr("x2", formatFloat(x2)).
+		setSvgAttr("y2", formatFloat(y2))
+}
+
+// Creates a <linearGradient> with the given id.  Add color stops with
+// Stop().
+func (t *SvgTag) LinearGradient(id string) *SvgTag {
+	newTag := t.addSvgChild("linearGradient")
+	newTag.SetId(id)
+	return newTag
+}
+
+// d may be built with PathData.
+func (t *SvgTag) Path(d string) *SvgTag {
+	return t.addSvgChild("path").setSvgAttr("d", d)
+}
+
+func (t *SvgTag) Polygon(points ...Point) *SvgTag {
+	return t.addSvgChild("polygon").setSvgAttr("points", formatPoints(points))
+}
+
+func (t *SvgTag) Polyline(points ...Point) *SvgTag {
+	return t.addSvgChild("polyline").setSvgAttr("points", formatPoints(points))
+}
+
+func (t *SvgTag) Rect(x, y, width, height float64) *SvgTag {
+	return t.addSvgChild("rect").
+		setSvgAttr("x", formatFloat(x)).
+		setSvgAttr("y", formatFloat(y)).
+		setSvgAttr("width", formatFloat(width)).
+		setSvgAttr("height", formatFloat(height))
+}
+
+func (t *SvgTag) SetFill(paint string) *SvgTag {
+	return t.setSvgAttr("fill", paint)
+}
+
+// Assigns the xlink:href attribute, which references another element or
+// resource.
+func (t *SvgTag) SetHref(href string) *SvgTag {
+	return t.setSvgAttr("xlink:href", href)
+}
+
+func (t *SvgTag) SetOpacity(opacity float64) *SvgTag {
+	return t.setSvgAttr("opacity", formatFloat(opacity))
+}
+
+func (t *SvgTag) SetStroke(paint string) *SvgTag {
+	return t.setSvgAttr("stroke", paint)
+}
+
+func (t *SvgTag) SetStrokeWidth(width float64) *SvgTag {
+	return t.setSvgAttr("stroke-width", formatFloat(width))
+}
+
+func (t *SvgTag) SetTransform(transform string) *SvgTag {
+	return t.setSvgAttr("transform", transform)
+}
+
+func (t *SvgTag) SetViewBox(viewBox ViewBox) *SvgTag {
+	return t.setSvgAttr("viewBox", viewBox.String())
+}
+
+// Creates a gradient stop at offset, which ranges from 0 to 1.
+func (t *SvgTag) Stop(offset float64, color string) *SvgTag {
+	return t.addSvgChild("stop").
+		setSvgAttr("offset", formatFloat(offset)).
+		setSvgAttr("stop-color", color)
+}
+
+// Creates a <symbol> with the given id, for reference by Use().
+func (t *SvgTag) Symbol(id string, viewBox *ViewBox) *SvgTag {
+	newTag := t.addSvgChild("symbol")
+	newTag.SetId(id)
+	if viewBox != nil {
+		newTag.SetViewBox(*viewBox)
+	}
+	return newTag
+}
+
+// Creates a <text> element at (x, y) holding the escaped text.
+func (t *SvgTag) Text(x, y float64, text string) *SvgTag {
+	newTag := t.addSvgChild("text").
+		setSvgAttr("x", formatFloat(x)).
+		setSvgAttr("y", formatFloat(y))
+	newTag.T(text)
+	return newTag
+}
+
+// Creates a <use> element that references href (e.g., "#icon").
+func (t *SvgTag) Use(href string) *SvgTag {
+	return t.addSvgChild("use").SetHref(href)
+}
+
+// The viewBox attribute of <svg> and <symbol>.
+type ViewBox struct {
+	MinX   float64
+	MinY   float64
+	Width  float64
+	Height float64
+}
+
+func (v ViewBox) String() string {
+	return strings.Join([]string{
+		formatFloat(v.MinX),
+		formatFloat(v.MinY),
+		formatFloat(v.Width),
+		formatFloat(v.Height),
+	}, " ")
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+// Copyright 2014, Kevin Ko <kevin@faveset.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlgen
+
+import (
+	"testing"
+)
+
+func TestPathData(t *testing.T) {
+	d := new(PathData).MoveTo(0, 0).LineTo(10.5, 0).
+		QuadTo(15, 5, 10, 10).CubicTo(5, 15, 0, 15, 0, 10).
+		ArcTo(5, 5, 0, false, true, 0, 0).Close()
+	const expected = "M 0 0 L 10.5 0 Q 15 5 10 10 C 5 15 0 15 0 10 A 5 5 0 0 1 0 0 Z"
+	if d.String() != expected {
+		t.Errorf("expected %q, got %q", expected, d.String())
+	}
+}
+
+func TestSvg(t *testing.T) {
+	const kCompare = `<div>
+  <svg height="50" viewBox="0 0 100 50" width="100" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">
+    <defs>
+      <linearGradient id="grad">
+        <stop offset="0" stop-color="red" />
+        <stop offset="1" stop-color="blue" />
+      </linearGradient>
+      <symbol id="dot" viewBox="0 0 2 2">
+        <circle cx="1" cy="1" r="1" />
+      </symbol>
+    </defs>
+    <g transform="translate(5,5)">
+      <rect fill="url(#grad)" height="20" width="40" x="0" y="0" />
+      <line stroke="black" stroke-width="0.5" x1="0" x2="40" y1="0" y2="20" />
+      <polyline points="0,0 10,5 20,0" />
+      <polygon points="0,0 5,10 10,0" />
+      <path d="M 0 0 L 5 5 Z" />
+      <ellipse cx="1" cy="2" rx="3" ry="4" />
+    </g>
+    <use xlink:href="#dot" />
+    <text x="0" y="45">
+      a &lt; b
+    </text>
+  </svg>
+</div>`
+
+	div := H.Div()
+	svg := div.Svg(&SvgOptions{
+		Height:  50,
+		ViewBox: &ViewBox{Width: 100, Height: 50},
+		Width:   100,
+	})
+	defs := svg.Defs()
+	grad := defs.LinearGradient("grad")
+	grad.Stop(0, "red")
+	grad.Stop(1, "blue")
+	defs.Symbol("dot", &ViewBox{Width: 2, Height: 2}).Circle(1, 1, 1)
+	g := svg.G().SetTransform("translate(5,5)")
+	g.Rect(0, 0, 40, 20).SetFill("url(#grad)")
+	g.Line(0, 0, 40, 20).SetStroke("black").SetStrokeWidth(0.5)
+	g.Polyline(Point{0, 0}, Point{10, 5}, Point{20, 0})
+	g.Polygon(Point{0, 0}, Point{5, 10}, Point{10, 0})
+	g.Path(new(PathData).MoveTo(0, 0).LineTo(5, 5).Close().String())
+	g.Ellipse(1, 2, 3, 4)
+	svg.Use("#dot")
+	svg.Text(0, 45, "a < b")
+
+	if err := compareHtml(div, kCompare, true); err != nil {
+		t.Error(err)
+	}
+
+	// Non-pretty output self-closes empty elements as well.
+	if err := compareHtml(H.Div().Svg().G().Path("M 0 0"), `<path d="M 0 0" />`, false); err != nil {
+		t.Error(err)
+	}
+	if err := compareHtml(g.Copy(), `<g transform="translate(5,5)" />`, false); err != nil {
+		t.Error(err)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"bytes"
 	"io"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type BodyTag struct {
@@ -15,7 +17,7 @@ type BodyTag struct {
 }
 
 func (t *BodyTag) SetOnload(script string) Tag {
-	return t.setAttr(kAttrOnload, script)
+	return t.setOn(EventLoad, script)
 }
 
 type CheckedInputTag struct {
@@ -48,7 +50,22 @@ func (t *commentTag) Copy() Tag {
 	return &commentTag{}
 }
 
-func (t *commentTag) write(writer io.Writer, env ...Environment) (n int, err error) {
+// Comments have no tag name.
+func (t *commentTag) tagStr() string {
+	return ""
+}
+
+func (t *commentTag) write(writer io.Writer, ctx *renderContext) (n int, err error) {
+	if ctx.mode == RenderModeHtmlMinified {
+		// Comments are stripped.
+		return
+	}
+	if ctx.mode == RenderModeXml && !ctx.inComment {
+		return t.writeXml(writer, func(buf io.Writer) (int, error) {
+			return t.write(buf, ctx)
+		}, ctx)
+	}
+
 	if count, err := io.WriteString(writer, "<!-- "); err != nil {
 		return n, err
 	} else {
@@ -57,7 +74,7 @@ func (t *commentTag) write(writer io.Writer, env ...Environment) (n int, err err
 
 	// Recursively write all children.
 	for _, childTag := range t.children {
-		if count, err := childTag.write(writer, env...); err != nil {
+		if count, err := childTag.write(writer, ctx); err != nil {
 			return n, err
 		} else {
 			n += count
@@ -72,8 +89,18 @@ func (t *commentTag) write(writer io.Writer, env ...Environment) (n int, err err
 	return
 }
 
-func (t *commentTag) writePretty(writer io.Writer, indent int, env ...Environment) (n int, err error) {
-	if count, err := writeIndent(writer, indent); err != nil {
+func (t *commentTag) writePretty(writer io.Writer, indent int, ctx *renderContext) (n int, err error) {
+	if ctx.mode == RenderModeHtmlMinified {
+		// Comments are stripped.
+		return
+	}
+	if ctx.mode == RenderModeXml && !ctx.inComment {
+		return t.writeXml(writer, func(buf io.Writer) (int, error) {
+			return t.writePretty(buf, indent, ctx)
+		}, ctx)
+	}
+
+	if count, err := writeIndent(writer, indent, ctx); err != nil {
 		return n, err
 	} else {
 		n += count
@@ -85,7 +112,7 @@ func (t *commentTag) writePretty(writer io.Writer, indent int, env ...Environmen
 		n += count
 	}
 
-	newIndent := indent + kIndentSpace
+	newIndent := indent + 1
 
 	// Recursively write all children.
 	for _, childTag := range t.children {
@@ -95,7 +122,7 @@ func (t *commentTag) writePretty(writer io.Writer, indent int, env ...Environmen
 		} else {
 			n += count
 		}
-		if count, err := childTag.writePretty(writer, newIndent, env...); err != nil {
+		if count, err := childTag.writePretty(writer, newIndent, ctx); err != nil {
 			return n, err
 		} else {
 			n += count
@@ -109,7 +136,7 @@ func (t *commentTag) writePretty(writer io.Writer, indent int, env ...Environmen
 		} else {
 			n += count
 		}
-		if count, err := writeIndent(writer, indent); err != nil {
+		if count, err := writeIndent(writer, indent, ctx); err != nil {
 			return n, err
 		} else {
 			n += count
@@ -131,19 +158,95 @@ func (t *commentTag) writePretty(writer io.Writer, indent int, env ...Environmen
 	return
 }
 
+type DetailsTag struct {
+	baseTag
+}
+
+// Returns true if the details are expanded.
+func (t *DetailsTag) Open() bool {
+	_, ok := t.attrs[kAttrOpen]
+	return ok
+}
+
+func (t *DetailsTag) SetOpen(open bool) {
+	t.setBoolAttr(kAttrOpen, open)
+}
+
+type DialogTag struct {
+	baseTag
+}
+
+// Returns true if the dialog is marked as modal.
+func (t *DialogTag) Modal() bool {
+	return t.customAttrs[kAttrPrefixAria+"modal"] == kHtmlTrue
+}
+
+// Returns true if the dialog is shown.
+func (t *DialogTag) Open() bool {
+	_, ok := t.attrs[kAttrOpen]
+	return ok
+}
+
+// Marks the dialog as modal for assistive technologies via aria-modal.  Only
+// use this for a dialog that scripts show with showModal(): markup alone can
+// only show a dialog non-modally, and aria-modal on such a dialog hides the
+// rest of the page from screen readers while it is still interactive.
+func (t *DialogTag) SetModal(modal bool) {
+	if modal {
+		t.SetAria("modal", kHtmlTrue)
+	} else {
+		t.RemoveAttribute(kAttrPrefixAria + "modal")
+	}
+}
+
+func (t *DialogTag) SetOpen(open bool) {
+	t.setBoolAttr(kAttrOpen, open)
+}
+
+// Buffers the comment written by write and then writes it to writer with
+// any "--" in its text broken up, as XML requires.
+func (t *commentTag) writeXml(writer io.Writer, write func(io.Writer) (int, error), ctx *renderContext) (int, error) {
+	ctx.inComment = true
+	defer func() {
+		ctx.inComment = false
+	}()
+
+	buf := new(bytes.Buffer)
+	if _, err := write(buf); err != nil {
+		return 0, err
+	}
+	comment := buf.String()
+	start := strings.Index(comment, "<!--") + len("<!--")
+	end := strings.LastIndex(comment, "-->")
+	text := comment[start:end]
+	for strings.Contains(text, "--") {
+		text = strings.Replace(text, "--", "- -", -1)
+	}
+	return io.WriteString(writer, comment[:start]+text+comment[end:])
+}
+
 // An htmlTag will write a DOCTYPE declaration as well.
 type htmlTag struct {
 	baseTag
+
+	// The DOCTYPE declaration, which is omitted if empty.
+	doctype string
 }
 
-func (t *htmlTag) write(writer io.Writer, env ...Environment) (n int, err error) {
-	// Always prepend a DOCTYPE declaration.
-	if count, err := io.WriteString(writer, "<!DOCTYPE html>"); err != nil {
+func (t *htmlTag) write(writer io.Writer, ctx *renderContext) (n int, err error) {
+	if ctx.mode == RenderModeXml {
+		if count, err := io.WriteString(writer, kXmlDeclaration); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
+	}
+	if count, err := io.WriteString(writer, t.doctype); err != nil {
 		return n, err
 	} else {
 		n += count
 	}
-	if count, err := t.baseTag.write(writer, env...); err != nil {
+	if count, err := t.baseTag.write(writer, ctx); err != nil {
 		return n, err
 	} else {
 		n += count
@@ -151,19 +254,28 @@ func (t *htmlTag) write(writer io.Writer, env ...Environment) (n int, err error)
 	return
 }
 
-func (t *htmlTag) writePretty(writer io.Writer, indent int, env ...Environment) (n int, err error) {
-	if count, err := writeIndent(writer, indent); err != nil {
-		return n, err
-	} else {
-		n += count
+func (t *htmlTag) writePretty(writer io.Writer, indent int, ctx *renderContext) (n int, err error) {
+	// Place each declaration on its own line.
+	prolog := make([]string, 0, 2)
+	if ctx.mode == RenderModeXml {
+		prolog = append(prolog, kXmlDeclaration)
 	}
-	// Always prepend a DOCTYPE declaration.
-	if count, err := io.WriteString(writer, "<!DOCTYPE html>\n"); err != nil {
-		return n, err
-	} else {
-		n += count
+	if len(t.doctype) > 0 {
+		prolog = append(prolog, t.doctype)
+	}
+	for _, decl := range prolog {
+		if count, err := writeIndent(writer, indent, ctx); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
+		if count, err := io.WriteString(writer, decl+"\n"); err != nil {
+			return n, err
+		} else {
+			n += count
+		}
 	}
-	if count, err := t.baseTag.writePretty(writer, indent, env...); err != nil {
+	if count, err := t.baseTag.writePretty(writer, indent, ctx); err != nil {
 		return n, err
 	} else {
 		n += count
@@ -177,22 +289,46 @@ type InputTag struct {
 
 // Clears all options except for name and type.
 func (t *InputTag) ResetOptions() {
+	delete(t.attrs, kAttrAccept)
 	delete(t.attrs, kAttrAction)
 	delete(t.attrs, kAttrAlt)
 	delete(t.attrs, kAttrAutocomplete)
+	delete(t.attrs, kAttrCapture)
 	delete(t.attrs, kAttrChecked)
+	delete(t.attrs, kAttrDirname)
 	delete(t.attrs, kAttrDisabled)
+	delete(t.attrs, kAttrEnterkeyhint)
+	delete(t.attrs, kAttrFormaction)
+	delete(t.attrs, kAttrFormenctype)
+	delete(t.attrs, kAttrFormmethod)
+	delete(t.attrs, kAttrFormnovalidate)
+	delete(t.attrs, kAttrFormtarget)
 	delete(t.attrs, kAttrHeight)
+	delete(t.attrs, kAttrInputmode)
+	delete(t.attrs, kAttrList)
+	delete(t.attrs, kAttrMax)
 	delete(t.attrs, kAttrMaxlength)
+	delete(t.attrs, kAttrMin)
+	delete(t.attrs, kAttrMinlength)
+	delete(t.attrs, kAttrMultiple)
+	delete(t.attrs, kAttrPattern)
+	delete(t.attrs, kAttrPlaceholder)
 	delete(t.attrs, kAttrReadonly)
+	delete(t.attrs, kAttrRequired)
 	delete(t.attrs, kAttrSize)
 	delete(t.attrs, kAttrSrc)
+	delete(t.attrs, kAttrStep)
 	delete(t.attrs, kAttrValue)
 	delete(t.attrs, kAttrWidth)
+	t.isCacheClean = false
 }
 
 // NOTE: existing options will not be reset unless explicitly assigned.
 func (t *InputTag) SetOptions(o *InputOptions) {
+	if len(o.Accept) > 0 {
+		t.attrs[kAttrAccept] = o.Accept
+	}
+
 	if len(o.Action) > 0 {
 		t.attrs[kAttrAction] = o.Action
 	}
@@ -201,8 +337,16 @@ func (t *InputTag) SetOptions(o *InputOptions) {
 		t.attrs[kAttrAlt] = o.Alt
 	}
 
-	if o.AutocompleteOff {
-		t.attrs[kAttrAutocomplete] = kHtmlOff
+	if len(o.Autocomplete) > 0 {
+		tokens := make([]string, len(o.Autocomplete))
+		for ii, token := range o.Autocomplete {
+			tokens[ii] = string(token)
+		}
+		t.attrs[kAttrAutocomplete] = strings.Join(tokens, " ")
+	}
+
+	if len(o.Capture) > 0 {
+		t.attrs[kAttrCapture] = string(o.Capture)
 	}
 
 	if o.Checked {
@@ -212,28 +356,68 @@ func (t *InputTag) SetOptions(o *InputOptions) {
 		t.attrs[kAttrChecked] = ""
 	}
 
+	if len(o.Dirname) > 0 {
+		t.attrs[kAttrDirname] = o.Dirname
+	}
+
 	if o.Disabled {
 		t.attrs[kAttrDisabled] = ""
 	}
 
-	if o.Height > 0 {
-		t.attrs[kAttrHeight] = strconv.Itoa(o.Height)
+	if len(o.Enterkeyhint) > 0 {
+		t.attrs[kAttrEnterkeyhint] = string(o.Enterkeyhint)
+	}
+
+	if len(o.Formaction) > 0 {
+		t.attrs[kAttrFormaction] = o.Formaction
+	}
+
+	if len(o.Formenctype) > 0 {
+		t.attrs[kAttrFormenctype] = o.Formenctype
+	}
+
+	if len(o.Formmethod) > 0 {
+		t.attrs[kAttrFormmethod] = o.Formmethod
+	}
+
+	if o.Formnovalidate {
+		t.attrs[kAttrFormnovalidate] = ""
+	}
+
+	if len(o.Formtarget) > 0 {
+		t.attrs[kAttrFormtarget] = o.Formtarget
+	}
+
+	if o.Height != nil {
+		t.attrs[kAttrHeight] = strconv.Itoa(*o.Height)
+	}
+
+	if len(o.Inputmode) > 0 {
+		t.attrs[kAttrInputmode] = string(o.Inputmode)
 	}
 
 	if len(o.List) > 0 {
 		t.attrs[kAttrList] = o.List
 	}
 
-	if o.Max != 0 {
-		t.attrs[kAttrMax] = strconv.FormatFloat(o.Max, 'g', -1, 64)
+	if o.Max != nil {
+		t.attrs[kAttrMax] = o.Max.String()
+	}
+
+	if o.Maxlength != nil {
+		t.attrs[kAttrMaxlength] = strconv.Itoa(*o.Maxlength)
+	}
+
+	if o.Min != nil {
+		t.attrs[kAttrMin] = o.Min.String()
 	}
 
-	if o.Maxlength > 0 {
-		t.attrs[kAttrMaxlength] = strconv.Itoa(o.Maxlength)
+	if o.Minlength != nil {
+		t.attrs[kAttrMinlength] = strconv.Itoa(*o.Minlength)
 	}
 
-	if o.Min != 0 {
-		t.attrs[kAttrMin] = strconv.FormatFloat(o.Min, 'g', -1, 64)
+	if o.Multiple {
+		t.attrs[kAttrMultiple] = ""
 	}
 
 	if len(o.Name) > 0 {
@@ -256,12 +440,12 @@ func (t *InputTag) SetOptions(o *InputOptions) {
 		t.attrs[kAttrRequired] = ""
 	}
 
-	if o.Size > 0 {
-		t.attrs[kAttrSize] = strconv.Itoa(o.Size)
+	if o.Size != nil {
+		t.attrs[kAttrSize] = strconv.Itoa(*o.Size)
 	}
 
-	if o.Step != 0 {
-		t.attrs[kAttrStep] = strconv.FormatFloat(o.Step, 'g', -1, 64)
+	if o.Step != nil {
+		t.attrs[kAttrStep] = o.Step.String()
 	}
 
 	if len(o.Src) > 0 {
@@ -278,8 +462,8 @@ func (t *InputTag) SetOptions(o *InputOptions) {
 		t.attrs[kAttrValue] = o.Value
 	}
 
-	if o.Width > 0 {
-		t.attrs[kAttrWidth] = strconv.Itoa(o.Width)
+	if o.Width != nil {
+		t.attrs[kAttrWidth] = strconv.Itoa(*o.Width)
 	}
 
 	t.isCacheClean = false
@@ -309,8 +493,10 @@ type nullTag struct {
 
 func newNullTag() *nullTag {
 	return &nullTag{baseTag{
-		tagType:  kTagTypeNull,
-		children: make([]tagWriter, 0),
+		tagType:     kTagTypeNull,
+		attrs:       make(map[int]string),
+		customAttrs: make(map[string]string),
+		children:    make([]tagWriter, 0),
 	}}
 }
 
@@ -319,10 +505,10 @@ func (t *nullTag) Copy() Tag {
 }
 
 // This just writes the children.
-func (t *nullTag) write(writer io.Writer, env ...Environment) (n int, err error) {
+func (t *nullTag) write(writer io.Writer, ctx *renderContext) (n int, err error) {
 	// Recursively write all children.
 	for _, childTag := range t.children {
-		if count, err := childTag.write(writer, env...); err != nil {
+		if count, err := childTag.write(writer, ctx); err != nil {
 			return n, err
 		} else {
 			n += count
@@ -331,21 +517,24 @@ func (t *nullTag) write(writer io.Writer, env ...Environment) (n int, err error)
 	return
 }
 
-func (t *nullTag) writePretty(writer io.Writer, indent int, env ...Environment) (n int, err error) {
-	for ii, childTag := range t.children {
-		if count, err := childTag.writePretty(writer, 0, env...); err != nil {
-			return n, err
-		} else {
-			n += count
+func (t *nullTag) writePretty(writer io.Writer, indent int, ctx *renderContext) (n int, err error) {
+	first := true
+	for _, childTag := range t.children {
+		if childTag.isHidden() {
+			continue
 		}
 
-		if ii == len(t.children)-1 {
-			// Avoid the final newline.
-			continue
+		if !first {
+			// Pretty print with newline between children.
+			if count, err := writeRune(writer, '\n'); err != nil {
+				return n, err
+			} else {
+				n += count
+			}
 		}
+		first = false
 
-		// Pretty print with newline.
-		if count, err := writeRune(writer, '\n'); err != nil {
+		if count, err := childTag.writePretty(writer, indent, ctx); err != nil {
 			return n, err
 		} else {
 			n += count
@@ -354,6 +543,29 @@ func (t *nullTag) writePretty(writer io.Writer, indent int, env ...Environment)
 	return
 }
 
+type OptgroupTag struct {
+	baseTag
+}
+
+// Returns the option children of the optgroup.
+func (t *OptgroupTag) OptionChildren() []*OptionTag {
+	return optionChildren(t.children)
+}
+
+// Returns the OptionTags in children, descending into optgroups.
+func optionChildren(children []tagWriter) []*OptionTag {
+	result := make([]*OptionTag, 0, len(children))
+	for _, child := range children {
+		switch tag := child.(type) {
+		case *OptionTag:
+			result = append(result, tag)
+		case *OptgroupTag:
+			result = append(result, tag.OptionChildren()...)
+		}
+	}
+	return result
+}
+
 type OptionTag struct {
 	baseTag
 }
@@ -405,30 +617,17 @@ func (t *OptionTag) Value() string {
 
 type SelectTag struct {
 	baseTag
-
-	options []*OptionTag
-}
-
-func (t *SelectTag) Option(options ...*OptionOptions) *OptionTag {
-	newTag := t.htmlGen.Option(options...)
-	t.children = append(t.children, newTag)
-	t.options = append(t.options, newTag)
-	return newTag
 }
 
+// Returns the option children of the select, including those nested within
+// optgroups.
 func (t *SelectTag) OptionChildren() []*OptionTag {
-	return t.options
-}
-
-func (t *SelectTag) RemoveChildren() Tag {
-	t.children = t.children[:0]
-	t.options = t.options[:0]
-	return t
+	return optionChildren(t.children)
 }
 
 // Returns the selected OptionTag.
 func (t *SelectTag) SelectedOption() (tag *OptionTag, err error) {
-	for _, optionTag := range t.options {
+	for _, optionTag := range t.OptionChildren() {
 		if optionTag.Selected() {
 			tag = optionTag
 			return
@@ -454,9 +653,9 @@ func newSingleTag(tagType int) *singleTag {
 
 func (t *singleTag) Copy() Tag {
 	if !t.isCacheClean {
-		tagStr := tagTypeStringMap[t.tagType]
+		tagStr := t.tagStr()
 		var err error
-		if t.cacheOpen, err = t.renderCacheOpen(tagStr); err == nil {
+		if t.cacheOpen, err = t.renderCacheOpen(tagStr, t.cacheMode); err == nil {
 			t.isCacheClean = true
 		}
 		// Otherwise, leave the cache dirty.
@@ -464,10 +663,14 @@ func (t *singleTag) Copy() Tag {
 
 	return &singleTag{baseTag{
 		tagType:      t.tagType,
+		name:         t.name,
 		attrs:        copyAttrs(t.attrs),
 		customAttrs:  copyCustomAttrs(t.customAttrs),
+		classes:      copyClasses(t.classes),
+		styles:       copyStyles(t.styles),
 		children:     nil,
 		isCacheClean: t.isCacheClean,
+		cacheMode:    t.cacheMode,
 		cacheOpen:    t.cacheOpen,
 	}}
 }
@@ -481,50 +684,41 @@ func (t *singleTag) RemoveChildren() Tag {
 	return t
 }
 
-// Renders the opening tag with attributes to a string.
-func (t *singleTag) renderCacheOpen(tagStr string) (result string, err error) {
-	tmp := new(bytes.Buffer)
-	if _, err = t.writeOpenTagLead(tmp, tagStr); err != nil {
-		return
+func (t *singleTag) write(writer io.Writer, ctx *renderContext) (n int, err error) {
+	tagStr := t.tagStr()
+
+	// Write the tag.
+	if count, err := t.writeOpenTagLeadCached(writer, tagStr, ctx); err != nil {
+		return n, err
+	} else {
+		n += count
 	}
 	// Finish the tag.
-	if _, err = io.WriteString(tmp, " />"); err != nil {
-		return
+	if count, err := io.WriteString(writer, voidTagEnd(ctx.mode)); err != nil {
+		return n, err
+	} else {
+		n += count
 	}
-	result = tmp.String()
 	return
 }
 
-func (t *singleTag) write(writer io.Writer, env ...Environment) (n int, err error) {
-	tagStr := tagTypeStringMap[t.tagType]
-
-	// Write the tag.
-	if !t.isCacheClean {
-		if t.cacheOpen, err = t.renderCacheOpen(tagStr); err != nil {
-			return
-		}
-		t.isCacheClean = true
-	}
-	return io.WriteString(writer, t.cacheOpen)
-}
-
-func (t *singleTag) writePretty(writer io.Writer, indent int, env ...Environment) (n int, err error) {
-	if count, err := writeIndent(writer, indent); err != nil {
+func (t *singleTag) writePretty(writer io.Writer, indent int, ctx *renderContext) (n int, err error) {
+	if count, err := writeIndent(writer, indent, ctx); err != nil {
 		return n, err
 	} else {
 		n += count
 	}
 
-	tagStr := tagTypeStringMap[t.tagType]
+	tagStr := t.tagStr()
 
 	// Write the tag.
-	if count, err := t.writeOpenTagLeadSorted(writer, tagStr); err != nil {
+	if count, err := t.writeOpenTagLeadSorted(writer, tagStr, indent, ctx); err != nil {
 		return n, err
 	} else {
 		n += count
 	}
 	// Finish the tag.
-	if count, err := io.WriteString(writer, " />"); err != nil {
+	if count, err := io.WriteString(writer, voidTagEnd(ctx.mode)); err != nil {
 		return n, err
 	} else {
 		n += count
@@ -544,20 +738,50 @@ type Tag interface {
 	AddChild(tag Tag) Tag
 	AddChildText(tag *TextTag) Tag
 
-	// Adds classes to tags set of classes.
+	// Adds classes to tags set of classes.  Classes that are already
+	// present are ignored, and each entry may hold several
+	// space-separated classes.
 	AddClass(classes ...string) Tag
 	AddClasses(classes []string) Tag
 
 	// Assigns the current tag to tag and returns it.
 	Assign(tag *Tag) Tag
 
+	// Attaches a declarative shadow root with the given mode to the tag
+	// and returns its <template>, to which the shadow tree should be
+	// added.  The template is rendered as the tag's first child.  If a
+	// shadow root is already attached, it is returned unchanged.
+	// options is optional, and only the first one will be used; its
+	// ShadowRootMode is ignored.  Returns ErrInvalidShadowRoot if mode is
+	// empty or the tag cannot host a shadow root, which is limited to
+	// custom elements and a few built-in elements, such as <div>.
+	AttachShadow(mode ShadowRootMode, options ...*TemplateOptions) (Tag, error)
+
+	// Returns a copy of the tag's classes in the order that they were
+	// added.
+	Classes() []string
+
 	// Returns a copy of the current tag.  Children will not be copied.
 	// The Tag's cache will be updated at the time of the copy so that
 	// the cache may be shared.
 	Copy() Tag
 
+	// Returns the value of the data-key attribute, or the empty string if
+	// it does not exist.  Values assigned by SetDataJSON are returned in
+	// their escaped form.
+	Data(key string) string
+
+	// Returns the value of the custom attribute key and whether it exists.
+	getAttribute(key string) (string, bool)
+
 	getChildren() []tagWriter
 
+	// Returns the element name.
+	tagStr() string
+
+	// Returns true if class is one of the tag's classes.
+	HasClass(class string) bool
+
 	// Hides the tag and its children during the rendering process.
 	Hide(isHidden bool)
 
@@ -570,6 +794,19 @@ type Tag interface {
 	// Removes all children of the given Tag and returns the Tag.
 	RemoveChildren() Tag
 
+	// Removes classes from the tag's set of classes.  Classes that are
+	// not present are ignored.
+	RemoveClass(classes ...string) Tag
+
+	// Removes the data-key attribute.
+	RemoveData(key string) Tag
+
+	// Removes the tabindex attribute.
+	RemoveTabindex() Tag
+
+	// Removes the given properties from the tag's inline style.
+	RemoveStyle(props ...string) Tag
+
 	// Removes the tag from its parent and returns itself.  This is
 	// optimized for removing the most recently added child of a parent,
 	// which will operate in O(1) time.
@@ -581,6 +818,33 @@ type Tag interface {
 	RemoveAttribute(key string) Tag
 	RemoveAttributes(keys ...string) Tag
 
+	// Global attributes.  Enumerated attributes are cleared by passing
+	// the empty string, and boolean attributes are cleared by passing
+	// false.
+	SetAccesskey(keys string) Tag
+	SetAutofocus(autofocus bool) Tag
+	SetContentEditable(value ContentEditable) Tag
+	SetDir(dir Dir) Tag
+	SetDraggable(value Draggable) Tag
+	// Sets the HTML hidden attribute.  Unlike Hide(), the tag is still
+	// rendered.
+	SetHidden(hidden bool) Tag
+	SetInert(inert bool) Tag
+	SetLang(lang string) Tag
+	SetSpellcheck(value Spellcheck) Tag
+	SetTabindex(index int) Tag
+	SetTranslate(value Translate) Tag
+
+	// Sets the aria-name attribute.  The empty string clears it.  name is
+	// case-insensitive, and names with characters other than letters are
+	// ignored.
+	SetAria(name, value string) Tag
+	// Helpers for common ARIA states and properties.
+	SetAriaDescribedby(ids ...string) Tag
+	SetAriaExpanded(expanded bool) Tag
+	SetAriaHidden(hidden bool) Tag
+	SetAriaLabel(label string) Tag
+
 	// Set a custom attribute.
 	SetAttribute(key, value string) Tag
 	SetAttributes(attrs map[string]string) Tag
@@ -590,8 +854,29 @@ type Tag interface {
 	SetClass(classes ...string) Tag
 	SetClasses(classes []string) Tag
 
+	// Sets the data-key attribute.  Like SetAttribute, value is written
+	// verbatim, and the empty string is a valid value.  A camelCase key is
+	// converted to kebab-case (e.g., "userId" becomes data-user-id) so
+	// that it maps back to the same key of the element's dataset.  Keys
+	// with characters other than letters, digits, '.', '_' and '-' are
+	// ignored.
+	SetData(key, value string) Tag
+	// Sets the data-key attribute to the JSON encoding of v.  Returns
+	// ErrInvalidDataKey if key is ignored by SetData.
+	SetDataJSON(key string, v interface{}) error
+
 	SetId(id string) Tag
 
+	// Sets the handler for the given event, which is rendered as the
+	// "on" + event attribute.  script is escaped for the attribute, so it
+	// should be given as plain JavaScript.  The empty string clears the
+	// handler.
+	SetOn(event EventName, script string) Tag
+
+	// The setters below are shorthands for common events.  Unlike SetOn,
+	// they write script verbatim, as they always have, so it must already
+	// be escaped for the attribute.
+
 	// Form events
 	SetOnblur(script string) Tag
 	SetOnchange(script string) Tag
@@ -619,20 +904,61 @@ type Tag interface {
 	// not exposed publicly.
 	setParent(parent Tag)
 
+	SetRole(role string) Tag
+
+	// Sets the inline style property prop to value, preserving the order
+	// in which properties were first set.  value is escaped for the
+	// attribute when written.  An empty value removes the property.
+	// Invalid property names are ignored, as are values with a ';', '{',
+	// '}' or comment outside of a string, or with an unterminated string,
+	// since they could end the declaration.
+	SetStyle(prop, value string) Tag
+
 	SetTitle(title string) Tag
 
+	// Returns a copy of the tag's inline style properties, with values as
+	// they were passed to SetStyle.
+	Styles() map[string]string
+
+	// Adds class if on is true; otherwise, removes it.
+	ToggleClass(class string, on bool) Tag
+	// Calls ToggleClass for each entry of classes.  Added classes are
+	// appended in sorted order.
+	ToggleClasses(classes ClassMap) Tag
+
 	// Move up count levels from tag.  Returns nil if no more ancestors
 	// exist.  count always defaults to 1 if not specified or non-positive.
 	Up(count ...int) Tag
 }
 
 type TagFactory interface {
-	A(href string) Tag
+	// href is omitted if empty.  options is optional, and only the first
+	// one will be used.
+	A(href string, options ...*AOptions) Tag
 	Abbr(title ...string) Tag
 	Address() Tag
 
+	// Sectioning elements.  The Id/Classes variants behave like those of
+	// Div.
+	Article() Tag
+	ArticleClasses(classes ...string) Tag
+	ArticleId(id string) Tag
+	ArticleIdClasses(id string, classes ...string) Tag
+	Aside() Tag
+	AsideClasses(classes ...string) Tag
+	AsideId(id string) Tag
+	AsideIdClasses(id string, classes ...string) Tag
+
+	// options is optional, and only the first one will be used.
+	Audio(options ...*AudioOptions) Tag
+
 	B() Tag
 
+	Bdi() Tag
+
+	// Overrides the bidirectional algorithm with dir.
+	Bdo(dir Dir) Tag
+
 	Blockquote() Tag
 
 	Body() *BodyTag
@@ -658,8 +984,24 @@ type TagFactory interface {
 
 	Datalist() Tag
 
+	// Creates a <data> element with a machine-readable value.  (Data()
+	// returns data-* attributes.)
+	DataValue(value string) Tag
+
+	// Edits.  options is optional, and only the first one will be used.
+	Del(options ...*EditOptions) Tag
+	Ins(options ...*EditOptions) Tag
+
+	// Creates a <details> element, which is expanded if open is true.
+	Details(open bool) *DetailsTag
+
 	Dfn() Tag
 
+	// Creates a <dialog> element, which is shown non-modally if open is
+	// true.  modal is a hint for assistive technologies, and is only for
+	// dialogs that scripts show with showModal(); see DialogTag.SetModal().
+	Dialog(open, modal bool) *DialogTag
+
 	// Creates a child Div element.
 	Div() Tag
 	// Creates a child Div element with specified classes.
@@ -673,8 +1015,30 @@ type TagFactory interface {
 	Dl() Tag
 	Dt() Tag
 
+	// Creates an element with an arbitrary name, such as a custom element
+	// (e.g., "my-widget") or one that lacks a dedicated constructor.
+	// Returns ErrInvalidElementName if name is not a lowercase element
+	// name, or if it is a reserved name.  Custom element names must
+	// contain a hyphen.
+	Element(name string) (Tag, error)
+
 	Em() Tag
 
+	Fieldset(options ...*FieldsetOptions) Tag
+
+	// Creates a void <embed> element.  src and embedType are omitted if
+	// empty.
+	Embed(src, embedType string, options ...*EmbedOptions) Tag
+
+	Figcaption() Tag
+	FigcaptionClasses(classes ...string) Tag
+	FigcaptionId(id string) Tag
+	FigcaptionIdClasses(id string, classes ...string) Tag
+	Figure() Tag
+	FigureClasses(classes ...string) Tag
+	FigureId(id string) Tag
+	FigureIdClasses(id string, classes ...string) Tag
+
 	Footer() Tag
 
 	// options need not be specified.  If it is, only the first will be
@@ -690,10 +1054,26 @@ type TagFactory interface {
 
 	Head() Tag
 
+	Header() Tag
+	HeaderClasses(classes ...string) Tag
+	HeaderId(id string) Tag
+	HeaderIdClasses(id string, classes ...string) Tag
+
+	Hgroup() Tag
+	HgroupClasses(classes ...string) Tag
+	HgroupId(id string) Tag
+	HgroupIdClasses(id string, classes ...string) Tag
+
 	Hr() Tag
 
 	I() Tag
 
+	// src is omitted if empty, which is useful with IframeOptions.Srcdoc.
+	// options is optional, and only the first one will be used.
+	Iframe(src string, options ...*IframeOptions) Tag
+
+	// An invalid ImgOptions.Srcset is omitted, leaving src as the only
+	// image; use Srcset.Validate() beforehand to detect this.
 	Img(src, alt string, options ...*ImgOptions) Tag
 
 	// options is optional, and only the first one will be used.
@@ -704,29 +1084,106 @@ type TagFactory interface {
 
 	Label(options ...*LabelOptions) Tag
 
+	// Creates a <legend> element for <fieldset>.  If given, only the first
+	// text is used, which is escaped and added as a child.
+	Legend(text ...string) Tag
+
 	Li() Tag
 
-	// options need not be specified; only the first will be used.
+	Main() Tag
+	MainClasses(classes ...string) Tag
+	MainId(id string) Tag
+	MainIdClasses(id string, classes ...string) Tag
+
+	// options need not be specified; only the first will be used.  An
+	// invalid LinkOptions.Imagesrcset is omitted; use Srcset.Validate()
+	// beforehand to detect this.
 	Link(rel string, options ...*LinkOptions) Tag
 
+	Mark() Tag
+
+	// Creates a <math> element.  Its descendants are created with the
+	// methods of MathTag.  options is optional, and only the first one
+	// will be used.
+	Math(options ...*MathOptions) *MathTag
+
+	Menu() Tag
+
 	Meta(name, content string, options ...*MetaOptions) Tag
 
+	// value is always rendered, defaulting to zero.
+	Meter(options ...*MeterOptions) Tag
+
+	Nav() Tag
+	NavClasses(classes ...string) Tag
+	NavId(id string) Tag
+	NavIdClasses(id string, classes ...string) Tag
+
 	NoScript() Tag
 
+	Object(options ...*ObjectOptions) Tag
+
 	Ol() Tag
 
+	// Creates an <optgroup> element for <select> with the required label.
+	Optgroup(label string, options ...*OptgroupOptions) *OptgroupTag
+
 	Option(options ...*OptionOptions) *OptionTag
 
+	Output(options ...*OutputOptions) Tag
+
 	P() Tag
+
+	// Creates a void <param> element for <object>.
+	Param(name, value string) Tag
+
+	// Creates a <picture> element holding a <source> child for each of
+	// sources followed by a fallback <img> with the given src, alt and
+	// options.  The <picture> element is returned.
+	Picture(src, alt string, sources []*SourceOptions, options ...*ImgOptions) Tag
+
 	Pre() Tag
 
+	Progress(options ...*ProgressOptions) Tag
+
+	// cite is the optional source URL of the quotation.
+	Q(cite ...string) Tag
+
+	// Ruby annotations.
+	Rp() Tag
+	Rt() Tag
+	Ruby() Tag
+
+	S() Tag
+
 	Samp() Tag
 
 	Script(scriptType ...string) Tag
-	ScriptSrc(scriptType, src string) Tag
+	// options is optional, and only the first one will be used.
+	ScriptImportMap(m *ImportMap, options ...*ScriptOptions) Tag
+	// options is optional, and only the first one will be used.
+	ScriptSrc(scriptType, src string, options ...*ScriptOptions) Tag
+
+	Search() Tag
+	SearchClasses(classes ...string) Tag
+	SearchId(id string) Tag
+	SearchIdClasses(id string, classes ...string) Tag
+
+	Section() Tag
+	SectionClasses(classes ...string) Tag
+	SectionId(id string) Tag
+	SectionIdClasses(id string, classes ...string) Tag
+
+	// Creates a <slot>, which is the default slot if name is empty.
+	Slot(name string) Tag
 
 	Small() Tag
 
+	// Creates a void <source> element for <audio>, <video> or <picture>.
+	// An invalid SourceOptions.Srcset is omitted; use Srcset.Validate()
+	// beforehand to detect this.
+	Source(options ...*SourceOptions) Tag
+
 	Span() Tag
 	SpanClasses(classes ...string) Tag
 	SpanId(id string) Tag
@@ -736,8 +1193,21 @@ type TagFactory interface {
 
 	Strong() Tag
 
+	Sub() Tag
+
+	// Creates a <summary> element for <details>.  If given, only the first
+	// text is used, which is escaped and added as a child.
+	Summary(text ...string) Tag
+
 	Style(options ...*StyleOptions) Tag
 
+	Sup() Tag
+
+	// Creates an inline <svg> element.  Its descendants are created with
+	// the methods of SvgTag.  options is optional, and only the first one
+	// will be used.
+	Svg(options ...*SvgOptions) *SvgTag
+
 	Table(options ...*TableOptions) Tag
 	Tbody() Tag
 	Td(options ...*TdOptions) Tag
@@ -747,14 +1217,29 @@ type TagFactory interface {
 	Title() Tag
 	Tr() Tag
 
+	// Creates a <template>, which declares a shadow root for its parent if
+	// options specifies a ShadowRootMode.  See also Tag.AttachShadow().
+	// options is optional, and only the first one will be used.
+	Template(options ...*TemplateOptions) Tag
+
 	Textarea(rows, cols int, options ...*TextareaOptions) Tag
 
+	// Creates a <time> element with a datetime attribute for tm.  The
+	// element text is tm formatted according to layout, if non-empty.
+	Time(tm time.Time, layout string) Tag
+
+	// Creates a void <track> element for <audio> or <video>.
+	Track(src string, options ...*TrackOptions) Tag
+
 	U() Tag
 
 	Ul() Tag
 
 	Var() Tag
 
+	// options is optional, and only the first one will be used.
+	Video(options ...*VideoOptions) Tag
+
 	// Creates a new TextTag with contents text; only the first variadic
 	// text is used.  If empty, an empty TextTag will be created.
 	// text will be escaped automatically.  If escaping is not desired, use
@@ -772,16 +1257,26 @@ type TagFactory interface {
 
 	// This is a version of TV() that does not escape text.
 	TVUnsafe(text ...string) *TextTagVar
+
+	// Like Element() but creates a void element, which has no children
+	// or closing tag.  name must be one that the HTML parser treats as
+	// void, such as "keygen", since any other element would be left open
+	// in RenderModeHtml.  Custom elements are never void.
+	VoidElement(name string) (Tag, error)
+
+	// Word break opportunity.
+	Wbr() Tag
 }
 
 type tagWriter interface {
 	// Returns true if the tag is hidden.
 	isHidden() bool
 
-	// env is an optional environment for variable substitution in any supported tags.
-	write(writer io.Writer, env ...Environment) (int, error)
+	// ctx holds the rendering options, including the environment for
+	// variable substitution in any supported tags.
+	write(writer io.Writer, ctx *renderContext) (int, error)
 
-	// Pretty printing.  Tag will be indented by 'indent' spaces.
+	// Pretty printing.  Tag will be indented by 'indent' levels.
 	// This returns the number of bytes written to the writer.
-	writePretty(writer io.Writer, indent int, env ...Environment) (int, error)
+	writePretty(writer io.Writer, indent int, ctx *renderContext) (int, error)
 }
@@ -7,6 +7,7 @@ package htmlgen
 import (
 	"html"
 	"io"
+	"time"
 )
 
 // A pseudo tag that holds text.
@@ -14,10 +15,13 @@ type TextTag struct {
 	parent *baseTag
 
 	text string
+
+	// True if the text holds unsafe text, which is written as given.
+	isUnsafe bool
 }
 
-func (t *TextTag) A(href string, text string) *TextTag {
-	t.parent.A(href).T(text)
+func (t *TextTag) A(href string, text string, options ...*AOptions) *TextTag {
+	t.parent.A(href, options...).T(text)
 	return t.parent.T()
 }
 
@@ -43,6 +47,16 @@ func (t *TextTag) BAssign(text string, tag **TextTag) *TextTag {
 	return t.parent.T()
 }
 
+func (t *TextTag) Bdi(text string) *TextTag {
+	t.parent.Bdi().T(text)
+	return t.parent.T()
+}
+
+func (t *TextTag) Bdo(dir Dir, text string) *TextTag {
+	t.parent.Bdo(dir).T(text)
+	return t.parent.T()
+}
+
 func (t *TextTag) Cite(text string) *TextTag {
 	t.parent.Cite().T(text)
 	return t.parent.T()
@@ -55,7 +69,17 @@ func (t *TextTag) Code(text string) *TextTag {
 
 // Returns a copy of the TextTag.
 func (t *TextTag) Copy() *TextTag {
-	return &TextTag{text: t.text}
+	return &TextTag{text: t.text, isUnsafe: t.isUnsafe}
+}
+
+func (t *TextTag) DataValue(value, text string) *TextTag {
+	t.parent.DataValue(value).T(text)
+	return t.parent.T()
+}
+
+func (t *TextTag) Del(text string, options ...*EditOptions) *TextTag {
+	t.parent.Del(options...).T(text)
+	return t.parent.T()
 }
 
 func (t *TextTag) Dfn(text string) *TextTag {
@@ -78,6 +102,11 @@ func (t *TextTag) Img(src, alt string, options ...*ImgOptions) *TextTag {
 	return t.parent.T()
 }
 
+func (t *TextTag) Ins(text string, options ...*EditOptions) *TextTag {
+	t.parent.Ins(options...).T(text)
+	return t.parent.T()
+}
+
 func (t *TextTag) isHidden() bool {
 	return false
 }
@@ -87,6 +116,11 @@ func (t *TextTag) Kbd(text string) *TextTag {
 	return t.parent.T()
 }
 
+func (t *TextTag) Mark(text string) *TextTag {
+	t.parent.Mark().T(text)
+	return t.parent.T()
+}
+
 // Returns the parent tag for t.
 func (t *TextTag) Parent() Tag {
 	return t.parent
@@ -97,6 +131,24 @@ func (t *TextTag) Pre(text string) *TextTag {
 	return t.parent.T()
 }
 
+func (t *TextTag) Q(text string, cite ...string) *TextTag {
+	t.parent.Q(cite...).T(text)
+	return t.parent.T()
+}
+
+// Annotates text with the ruby text rt.
+func (t *TextTag) Ruby(text, rt string) *TextTag {
+	ruby := t.parent.Ruby()
+	ruby.T(text)
+	ruby.Rt().T(rt)
+	return t.parent.T()
+}
+
+func (t *TextTag) S(text string) *TextTag {
+	t.parent.S().T(text)
+	return t.parent.T()
+}
+
 func (t *TextTag) Samp(text string) *TextTag {
 	t.parent.Samp().T(text)
 	return t.parent.T()
@@ -118,6 +170,16 @@ func (t *TextTag) Strong(text string) *TextTag {
 	return t.parent.T()
 }
 
+func (t *TextTag) Sub(text string) *TextTag {
+	t.parent.Sub().T(text)
+	return t.parent.T()
+}
+
+func (t *TextTag) Sup(text string) *TextTag {
+	t.parent.Sup().T(text)
+	return t.parent.T()
+}
+
 func (t *TextTag) T(text ...string) *TextTag {
 	if len(text) == 0 {
 		return t
@@ -131,11 +193,17 @@ func (t *TextTag) Text() string {
 	return t.text
 }
 
+func (t *TextTag) Time(tm time.Time, layout string) *TextTag {
+	t.parent.Time(tm, layout)
+	return t.parent.T()
+}
+
 func (t *TextTag) TUnsafe(text ...string) *TextTag {
 	if len(text) == 0 {
 		return t
 	}
 	t.text += text[0]
+	t.isUnsafe = true
 	return t
 }
 
@@ -173,17 +241,25 @@ func (t *TextTag) Var(text string) *TextTag {
 	return t.parent.T()
 }
 
-func (t *TextTag) write(writer io.Writer, env ...Environment) (int, error) {
-	return io.WriteString(writer, t.text)
+func (t *TextTag) Wbr() *TextTag {
+	t.parent.Wbr()
+	return t.parent.T()
+}
+
+func (t *TextTag) write(writer io.Writer, ctx *renderContext) (int, error) {
+	if t.isUnsafe {
+		return io.WriteString(writer, ctx.unsafeText(t.text))
+	}
+	return io.WriteString(writer, ctx.text(t.text))
 }
 
-func (t *TextTag) writePretty(writer io.Writer, indent int, env ...Environment) (n int, err error) {
-	if count, err := writeIndent(writer, indent); err != nil {
+func (t *TextTag) writePretty(writer io.Writer, indent int, ctx *renderContext) (n int, err error) {
+	if count, err := writeIndent(writer, indent, ctx); err != nil {
 		return n, err
 	} else {
 		n += count
 	}
-	if count, err := t.write(writer); err != nil {
+	if count, err := t.write(writer, ctx); err != nil {
 		return n, err
 	} else {
 		n += count
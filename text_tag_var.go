@@ -5,10 +5,12 @@
 package htmlgen
 
 import (
+	"bytes"
 	"html"
 	"io"
 	"regexp"
 	"strings"
+	"time"
 )
 
 const (
@@ -57,8 +59,8 @@ func NewTextTagVar(text string) *TextTagVar {
 	}
 }
 
-func (t *TextTagVar) A(href string, text string) *TextTagVar {
-	t.parent.A(href).TV(text)
+func (t *TextTagVar) A(href string, text string, options ...*AOptions) *TextTagVar {
+	t.parent.A(href, options...).TV(text)
 	return t.parent.TV()
 }
 
@@ -72,6 +74,16 @@ func (t *TextTagVar) B(text string) *TextTagVar {
 	return t.parent.TV()
 }
 
+func (t *TextTagVar) Bdi(text string) *TextTagVar {
+	t.parent.Bdi().TV(text)
+	return t.parent.TV()
+}
+
+func (t *TextTagVar) Bdo(dir Dir, text string) *TextTagVar {
+	t.parent.Bdo(dir).TV(text)
+	return t.parent.TV()
+}
+
 func (t *TextTagVar) Cite(text string) *TextTagVar {
 	t.parent.Cite().TV(text)
 	return t.parent.TV()
@@ -82,6 +94,16 @@ func (t *TextTagVar) Code(text string) *TextTagVar {
 	return t.parent.TV()
 }
 
+func (t *TextTagVar) DataValue(value, text string) *TextTagVar {
+	t.parent.DataValue(value).TV(text)
+	return t.parent.TV()
+}
+
+func (t *TextTagVar) Del(text string, options ...*EditOptions) *TextTagVar {
+	t.parent.Del(options...).TV(text)
+	return t.parent.TV()
+}
+
 func (t *TextTagVar) Dfn(text string) *TextTagVar {
 	t.parent.Dfn().TV(text)
 	return t.parent.TV()
@@ -102,6 +124,11 @@ func (t *TextTagVar) Img(src, alt string, options ...*ImgOptions) *TextTagVar {
 	return t.parent.TV()
 }
 
+func (t *TextTagVar) Ins(text string, options ...*EditOptions) *TextTagVar {
+	t.parent.Ins(options...).TV(text)
+	return t.parent.TV()
+}
+
 func (t *TextTagVar) isHidden() bool {
 	return false
 }
@@ -111,6 +138,11 @@ func (t *TextTagVar) Kbd(text string) *TextTagVar {
 	return t.parent.TV()
 }
 
+func (t *TextTagVar) Mark(text string) *TextTagVar {
+	t.parent.Mark().TV(text)
+	return t.parent.TV()
+}
+
 // Returns the parent tag for t.
 func (t *TextTagVar) Parent() Tag {
 	return t.parent
@@ -121,6 +153,24 @@ func (t *TextTagVar) Pre(text string) *TextTagVar {
 	return t.parent.TV()
 }
 
+func (t *TextTagVar) Q(text string, cite ...string) *TextTagVar {
+	t.parent.Q(cite...).TV(text)
+	return t.parent.TV()
+}
+
+// Annotates text with the ruby text rt.
+func (t *TextTagVar) Ruby(text, rt string) *TextTagVar {
+	ruby := t.parent.Ruby()
+	ruby.TV(text)
+	ruby.Rt().TV(rt)
+	return t.parent.TV()
+}
+
+func (t *TextTagVar) S(text string) *TextTagVar {
+	t.parent.S().TV(text)
+	return t.parent.TV()
+}
+
 func (t *TextTagVar) Samp(text string) *TextTagVar {
 	t.parent.Samp().TV(text)
 	return t.parent.TV()
@@ -147,10 +197,25 @@ func (t *TextTagVar) Strong(text string) *TextTagVar {
 	return t.parent.TV()
 }
 
+func (t *TextTagVar) Sub(text string) *TextTagVar {
+	t.parent.Sub().TV(text)
+	return t.parent.TV()
+}
+
+func (t *TextTagVar) Sup(text string) *TextTagVar {
+	t.parent.Sup().TV(text)
+	return t.parent.TV()
+}
+
 func (t *TextTagVar) T(text ...string) *TextTag {
 	return t.parent.T(text...)
 }
 
+func (t *TextTagVar) Time(tm time.Time, layout string) *TextTagVar {
+	t.parent.Time(tm, layout)
+	return t.parent.TV()
+}
+
 func (t *TextTagVar) TUnsafe(text ...string) *TextTag {
 	return t.parent.TUnsafe(text...)
 }
@@ -178,14 +243,28 @@ func (t *TextTagVar) Var(text string) *TextTagVar {
 	return t.parent.TV()
 }
 
-func (t *TextTagVar) write(writer io.Writer, optEnv ...Environment) (count int, err error) {
-	var env Environment
-	if len(optEnv) > 0 {
-		env = optEnv[0]
-	} else {
-		env = make(map[string]Value)
+func (t *TextTagVar) Wbr() *TextTagVar {
+	t.parent.Wbr()
+	return t.parent.TV()
+}
+
+func (t *TextTagVar) write(writer io.Writer, ctx *renderContext) (int, error) {
+	if !ctx.convertsText() {
+		return t.writeText(writer, ctx.env)
+	}
+
+	buf := new(bytes.Buffer)
+	if _, err := t.writeText(buf, ctx.env); err != nil {
+		return 0, err
 	}
+	if t.isUnsafe {
+		return io.WriteString(writer, ctx.unsafeText(buf.String()))
+	}
+	return io.WriteString(writer, ctx.text(buf.String()))
+}
 
+// Writes the text with variables expanded from env, which may be nil.
+func (t *TextTagVar) writeText(writer io.Writer, env Environment) (count int, err error) {
 	offset := 0
 	for _, v := range t.vars {
 		text := t.text[offset:v.startOffset]
@@ -250,14 +329,14 @@ func (t *TextTagVar) write(writer io.Writer, optEnv ...Environment) (count int,
 	return
 }
 
-func (t *TextTagVar) writePretty(writer io.Writer, indent int, env ...Environment) (int, error) {
+func (t *TextTagVar) writePretty(writer io.Writer, indent int, ctx *renderContext) (int, error) {
 	count := 0
-	if n, err := writeIndent(writer, indent); err != nil {
+	if n, err := writeIndent(writer, indent, ctx); err != nil {
 		return count, err
 	} else {
 		count += n
 	}
-	if n, err := t.write(writer, env...); err != nil {
+	if n, err := t.write(writer, ctx); err != nil {
 		return count, err
 	} else {
 		count += n
@@ -137,7 +137,7 @@ func TestExpand(t *testing.T) {
 		}
 
 		buf := bytes.Buffer{}
-		n, err := tag.write(&buf, test.env)
+		n, err := tag.write(&buf, newRenderContext(&RenderOptions{Env: test.env}))
 		if err != nil {
 			t.Error(err)
 		}